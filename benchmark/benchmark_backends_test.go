@@ -0,0 +1,86 @@
+package benchmark
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/thomasjungblut/go-dancing-links/dlx"
+	"github.com/thomasjungblut/go-dancing-links/nqueens"
+	"testing"
+)
+
+var backends = []dlx.Backend{dlx.LinkedListBackend, dlx.DancingCellsBackend, dlx.BitsetBackend}
+
+func BenchmarkBackendsNQueens(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c, err := nqueens.NewNQueensBoard(10, dlx.WithBackend(backend)).CountAllSolutions()
+				assert.Nil(b, err)
+				assert.Equal(b, 724, c)
+			}
+		})
+	}
+}
+
+func BenchmarkBackendsEuler96(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.String(), func(b *testing.B) {
+			eulerBoards, err := readAllEulerBoards(dlx.WithBackend(backend))
+			assert.Nil(b, err)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, board := range eulerBoards {
+					_, err := board.FindSingleSolution()
+					assert.Nil(b, err)
+				}
+			}
+		})
+	}
+}
+
+// builds the matrix only once, so only the search itself is measured
+func BenchmarkBackendsSearch(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.String(), func(b *testing.B) {
+			mat := newNQueensMatrix(b, 10, dlx.WithBackend(backend))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				assert.Equal(b, 724, mat.Count())
+			}
+		})
+	}
+}
+
+func BenchmarkBackendsSolve(b *testing.B) {
+	for _, backend := range backends {
+		b.Run(backend.String(), func(b *testing.B) {
+			mat := newNQueensMatrix(b, 10, dlx.WithBackend(backend))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				assert.Equal(b, 724, len(mat.Solve()))
+			}
+		})
+	}
+}
+
+// taking the first solutions from the suspended search doesn't need to look at the others
+func BenchmarkFirstSolutions(b *testing.B) {
+	mat := newNQueensMatrix(b, 12)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		it := mat.Iterate()
+		for j := 0; j < 10; j++ {
+			_, ok := it.Next()
+			assert.True(b, ok)
+		}
+		it.Close()
+	}
+}
+
+func newNQueensMatrix(tb testing.TB, n int, options ...dlx.MatrixOption) dlx.DancingLinksMatrixI {
+	mat, err := nqueens.NewNQueensMatrix(n, options...)
+	assert.Nil(tb, err)
+	return mat
+}
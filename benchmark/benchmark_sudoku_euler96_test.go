@@ -3,6 +3,7 @@ package benchmark
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/thomasjungblut/go-dancing-links/dlx"
 	"github.com/thomasjungblut/go-dancing-links/sudoku"
 	"io/ioutil"
 	"os"
@@ -51,7 +52,7 @@ func BenchmarkSolvingAllEulerPuzzlesHappyPath(t *testing.B) {
 	wg.Wait()
 }
 
-func readAllEulerBoards() ([]sudoku.SudokuBoardI, error) {
+func readAllEulerBoards(options ...dlx.MatrixOption) ([]sudoku.SudokuBoardI, error) {
 	txt, err := ioutil.ReadFile("p096_sudoku.txt")
 	if err != nil {
 		return nil, err
@@ -63,7 +64,7 @@ func readAllEulerBoards() ([]sudoku.SudokuBoardI, error) {
 		if len(grid) == 0 {
 			continue
 		}
-		board := sudoku.NewSudokuBoard(9)
+		board := sudoku.NewSudokuBoard(9, options...)
 		err = board.ReadEulerTextFormat(grid)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,142 @@
+package dlx
+
+import "fmt"
+
+// assumptions is the stack of rows that were forced by Push, together with the columns that were covered on their
+// own by CoverColumn. Both have to be undone in the exact reverse order, so we remember how they interleave.
+type assumptions struct {
+	frames []assumptionFrame
+	// the columns covered by CoverColumn in the order they were covered
+	covers []int
+}
+
+// assumptionFrame holds the rows that were forced by a single Push and the columns that were covered for them
+type assumptionFrame struct {
+	rows    []int
+	columns []int
+	// the number of columns that were covered on their own before the frame was pushed
+	numCovers int
+}
+
+func (a *assumptions) push(backend coverBackend, rowIdentifiers []string) error {
+	rowIndices := make([]int, len(rowIdentifiers))
+	for i, rowIdentifier := range rowIdentifiers {
+		rowIndex, ok := backend.RowIndex(rowIdentifier)
+		if !ok {
+			return fmt.Errorf("row %s does not exist", rowIdentifier)
+		}
+		rowIndices[i] = rowIndex
+	}
+	return a.pushRows(backend, rowIndices)
+}
+
+// pushRows forces the rows at the given indices as a single frame
+func (a *assumptions) pushRows(backend coverBackend, rowIndices []int) error {
+	frame := assumptionFrame{numCovers: len(a.covers)}
+	for _, rowIndex := range rowIndices {
+		// check the whole row first, so we don't have to revert half of it
+		columns := backend.rowColumns(rowIndex)
+		for _, c := range columns {
+			if backend.isCovered(c) {
+				a.undo(backend, frame)
+				return fmt.Errorf("cannot force row %s, its column %s is already covered",
+					backend.Rows()[rowIndex], backend.Columns()[c])
+			}
+		}
+
+		// covering the first column hides the row from all the others, so we can't collide with ourselves
+		for _, c := range columns {
+			backend.cover(c)
+			frame.columns = append(frame.columns, c)
+		}
+		frame.rows = append(frame.rows, rowIndex)
+	}
+
+	a.frames = append(a.frames, frame)
+	return nil
+}
+
+func (a *assumptions) pop(backend coverBackend) error {
+	if len(a.frames) == 0 {
+		return fmt.Errorf("there are no assumptions to pop")
+	}
+
+	top := a.frames[len(a.frames)-1]
+	if len(a.covers) > top.numCovers {
+		return fmt.Errorf("cannot pop while column at %d is covered after the last assumption was pushed",
+			a.covers[len(a.covers)-1])
+	}
+
+	a.undo(backend, top)
+	a.frames = a.frames[:len(a.frames)-1]
+	return nil
+}
+
+// coverColumn covers the column on its own, the column must exist
+func (a *assumptions) coverColumn(backend coverBackend, columnIndex int) error {
+	if a.owner(columnIndex) >= 0 {
+		return fmt.Errorf("column at %d is covered by an assumption", columnIndex)
+	}
+	if backend.isCovered(columnIndex) {
+		return fmt.Errorf("column at %d is already covered", columnIndex)
+	}
+
+	backend.cover(columnIndex)
+	a.covers = append(a.covers, columnIndex)
+	return nil
+}
+
+// uncoverColumn undoes coverColumn, which is only possible as long as no frame was pushed on top of it
+func (a *assumptions) uncoverColumn(backend coverBackend, columnIndex int) error {
+	if a.owner(columnIndex) >= 0 {
+		return fmt.Errorf("column at %d is covered by an assumption", columnIndex)
+	}
+	if !backend.isCovered(columnIndex) {
+		return fmt.Errorf("column at %d has not been covered yet", columnIndex)
+	}
+
+	pos := len(a.covers) - 1
+	for a.covers[pos] != columnIndex {
+		pos--
+	}
+	if len(a.frames) > 0 && pos < a.frames[len(a.frames)-1].numCovers {
+		return fmt.Errorf("column at %d was covered before the last assumption was pushed", columnIndex)
+	}
+
+	backend.uncover(columnIndex)
+	a.covers = append(a.covers[:pos], a.covers[pos+1:]...)
+	return nil
+}
+
+// returns the index of the frame that covered the column, -1 if no frame covered it
+func (a *assumptions) owner(columnIndex int) int {
+	for f, frame := range a.frames {
+		for _, c := range frame.columns {
+			if c == columnIndex {
+				return f
+			}
+		}
+	}
+	return -1
+}
+
+func (a *assumptions) size() int {
+	return len(a.frames)
+}
+
+// uncovering has to happen in the exact reverse order of covering to restore the matrix
+func (a *assumptions) undo(backend coverBackend, frame assumptionFrame) {
+	for i := len(frame.columns) - 1; i >= 0; i-- {
+		backend.uncover(frame.columns[i])
+	}
+}
+
+// every solution starts with the rows that are forced by the current assumptions
+func (a *assumptions) newPartialSolution() []int {
+	// allocate an empty slice with 100 capacity to avoid enlarging it all the time
+	partialSolution := make([]int, 0, 100)
+	for _, frame := range a.frames {
+		partialSolution = append(partialSolution, frame.rows...)
+	}
+	return partialSolution
+}
@@ -0,0 +1,113 @@
+package dlx
+
+// Backbone classifies every row by the solutions it is part of
+type Backbone struct {
+	// the rows that are part of every solution
+	Forced []string
+	// the rows that are part of no solution
+	Impossible []string
+	// the rows that are part of some solutions, but not of all
+	Optional []string
+}
+
+// backbone needs one search per row at most: every solution it finds proves all of its rows possible and all other
+// rows not forced, so those rows don't need a search of their own anymore.
+func backbone(backend coverBackend, a *assumptions) (*Backbone, error) {
+	numRows := len(backend.Rows())
+	excluded := make([]bool, numRows)
+	solveOne := func() []int {
+		var result []int
+		s := newSearcher(backend, nil, nil, nil, func(solution []int) bool {
+			result = append([]int{}, solution...)
+			return false
+		})
+		s.excluded = excluded
+		s.search(a.newPartialSolution())
+		return result
+	}
+
+	first := solveOne()
+	if first == nil {
+		return nil, nil
+	}
+
+	possible := make([]bool, numRows)
+	// a row can only be forced while it is part of every solution found so far
+	forced := make([]bool, numRows)
+	for _, r := range first {
+		possible[r] = true
+		forced[r] = true
+	}
+	learn := func(solution []int) {
+		inSolution := make([]bool, numRows)
+		for _, r := range solution {
+			inSolution[r] = true
+			possible[r] = true
+		}
+		for r := range forced {
+			forced[r] = forced[r] && inSolution[r]
+		}
+	}
+
+	impossible := make([]bool, numRows)
+	for r := 0; r < numRows; r++ {
+		if possible[r] {
+			continue
+		}
+		// the search only chooses rows to cover a primary column, and a row that conflicts with the covered
+		// columns can't be forced
+		if !coversPrimary(backend, r) || a.pushRows(backend, []int{r}) != nil {
+			impossible[r] = true
+			continue
+		}
+		solution := solveOne()
+		if err := a.pop(backend); err != nil {
+			return nil, err
+		}
+		if solution == nil {
+			impossible[r] = true
+		} else {
+			learn(solution)
+		}
+	}
+
+	// the rows forced by the assumptions are part of every solution already
+	pushed := make([]bool, numRows)
+	for _, r := range a.newPartialSolution() {
+		pushed[r] = true
+	}
+	for r := 0; r < numRows; r++ {
+		if !forced[r] || pushed[r] {
+			continue
+		}
+		excluded[r] = true
+		solution := solveOne()
+		excluded[r] = false
+		if solution != nil {
+			learn(solution)
+		}
+	}
+
+	rows := backend.Rows()
+	result := &Backbone{}
+	for r, row := range rows {
+		switch {
+		case forced[r]:
+			result.Forced = append(result.Forced, row)
+		case impossible[r]:
+			result.Impossible = append(result.Impossible, row)
+		default:
+			result.Optional = append(result.Optional, row)
+		}
+	}
+	return result, nil
+}
+
+func coversPrimary(backend coverBackend, rowIndex int) bool {
+	for _, c := range backend.rowColumns(rowIndex) {
+		if backend.isPrimary(c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestBackbone(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		assert.Equal(t, &Backbone{Impossible: []string{"Jack"}, Optional: []string{"Amanda", "Chris", "Jen"}},
+			backboneOf(t, mat))
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		mat = NewWikipediaExampleMatrix(t, backend)
+		assert.Equal(t, &Backbone{Forced: []string{"B", "D", "F"}, Impossible: []string{"A", "C", "E"}},
+			backboneOf(t, mat))
+	})
+}
+
+func TestBackboneWithoutSolution(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newTriangleMatrix(t, backend)
+		assert.Nil(t, backboneOf(t, mat))
+	})
+}
+
+func TestBackboneAssumptions(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.Push("Amanda"))
+		assert.Equal(t, &Backbone{Forced: []string{"Amanda", "Chris"}, Impossible: []string{"Jack", "Jen"}},
+			backboneOf(t, mat))
+		assert.Equal(t, 1, mat.NumAssumptions())
+		assert.Nil(t, mat.Pop())
+
+		// rows hidden by a covered column are part of no solution
+		assert.Nil(t, mat.CoverColumn(2))
+		assert.Equal(t, &Backbone{Forced: []string{"Amanda"}, Impossible: []string{"Jack", "Chris", "Jen"}},
+			backboneOf(t, mat))
+		assert.Nil(t, mat.UncoverColumn(2))
+	})
+}
+
+// the probes are pushed on top of a column covered after the last Push and popped before it is uncovered
+func TestBackboneWithCoverAfterAssumption(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		assert.Nil(t, mat.Push("Amanda"))
+		assert.Nil(t, mat.CoverColumn(2))
+		assert.Equal(t, &Backbone{Forced: []string{"Amanda"}, Impossible: []string{"Jack", "Chris", "Jen"}},
+			backboneOf(t, mat))
+		assert.Equal(t, 1, mat.NumAssumptions())
+		assert.Nil(t, mat.UncoverColumn(2))
+		assert.Nil(t, mat.Pop())
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
+}
+
+func TestBackboneSecondaryOnlyRow(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewDancingLinkMatrix(backend)
+		assert.Nil(t, mat.AppendColumn("a"))
+		assert.Nil(t, mat.AppendSecondaryColumn("s"))
+		assert.Nil(t, mat.AppendRow("X", []bool{true, false}))
+		assert.Nil(t, mat.AppendRow("S", []bool{false, true}))
+		// the search never chooses S, it only covers a secondary column
+		assert.Equal(t, [][]string{{"X"}}, mat.Solve())
+		assert.Equal(t, &Backbone{Forced: []string{"X"}, Impossible: []string{"S"}}, backboneOf(t, mat))
+	})
+}
+
+func TestBackboneAgreesWithSolveOnSecondaryColumns(t *testing.T) {
+	random := rand.New(rand.NewSource(8))
+	for i := 0; i < 50; i++ {
+		c := newRandomCase(random, 6, 10)
+		forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+			mat := c.build(t, backend)
+			assert.Equal(t, backboneFromSolutions(mat.Rows(), mat.Solve()), backboneOf(t, mat),
+				"matrix %v, primary %v", c.dense, c.primary)
+		})
+	}
+}
+
+func TestBackboneAgreesWithSolve(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		numColumns := 2 + random.Intn(5)
+		numRows := 1 + random.Intn(12)
+		dense := make([][]bool, numRows)
+		for r := range dense {
+			dense[r] = make([]bool, numColumns)
+			for c := range dense[r] {
+				dense[r][c] = random.Float64() < 0.35
+			}
+		}
+
+		forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+			mat := NewDancingLinkMatrix(backend)
+			for c := 0; c < numColumns; c++ {
+				assert.Nil(t, mat.AppendColumn(fmt.Sprintf("%d", c)))
+			}
+			for r, row := range dense {
+				assert.Nil(t, mat.AppendRow(fmt.Sprintf("r%d", r), row))
+			}
+			assert.Equal(t, backboneFromSolutions(mat.Rows(), mat.Solve()), backboneOf(t, mat), "matrix %v", dense)
+		})
+	}
+}
+
+func backboneOf(t *testing.T, mat DancingLinksMatrixI) *Backbone {
+	backbone, err := mat.Backbone()
+	assert.Nil(t, err)
+	return backbone
+}
+
+func backboneFromSolutions(rows []string, solutions [][]string) *Backbone {
+	if solutions == nil {
+		return nil
+	}
+	occurrences := map[string]int{}
+	for _, solution := range solutions {
+		for _, row := range solution {
+			occurrences[row]++
+		}
+	}
+	result := &Backbone{}
+	for _, row := range rows {
+		switch occurrences[row] {
+		case len(solutions):
+			result.Forced = append(result.Forced, row)
+		case 0:
+			result.Impossible = append(result.Impossible, row)
+		default:
+			result.Optional = append(result.Optional, row)
+		}
+	}
+	return result
+}
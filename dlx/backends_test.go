@@ -0,0 +1,117 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strings"
+	"testing"
+)
+
+var allBackends = []Backend{LinkedListBackend, DancingCellsBackend, BitsetBackend}
+
+// forEachBackend runs the test for all backends, every backend has to fulfill the same contract
+func forEachBackend(t *testing.T, test func(t *testing.T, backend MatrixOption)) {
+	for _, backend := range allBackends {
+		t.Run(backend.String(), func(t *testing.T) {
+			test(t, WithBackend(backend))
+		})
+	}
+}
+
+// backends may find the solutions in different orders, so we compare them sorted
+func assertSameSolutions(t *testing.T, expected [][]string, actual [][]string) {
+	assert.Equal(t, normalizeSolutions(expected), normalizeSolutions(actual))
+}
+
+func normalizeSolutions(solutions [][]string) []string {
+	var normalized []string
+	for _, solution := range solutions {
+		c := append([]string{}, solution...)
+		sort.Strings(c)
+		normalized = append(normalized, strings.Join(c, ","))
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func TestBackendRenderingIsTheSame(t *testing.T) {
+	expected := &strings.Builder{}
+	mat := NewWikipediaExampleMatrix(t)
+	assert.Nil(t, mat.CoverColumn(3))
+	assert.Nil(t, mat.CoverColumn(2))
+	assert.Nil(t, mat.WriteSVG(expected))
+
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		assert.Nil(t, mat.CoverColumn(3))
+		assert.Nil(t, mat.CoverColumn(2))
+		actual := &strings.Builder{}
+		assert.Nil(t, mat.WriteSVG(actual))
+		assert.Equal(t, expected.String(), actual.String())
+	})
+}
+
+func TestBackendTracing(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		trace := mat.TraceSearch(100)
+		assert.Equal(t, 1, len(mat.Solve()))
+		assert.Equal(t, 6, trace.NumNodes())
+	})
+}
+
+func TestBackendsAgreeOnNQueens(t *testing.T) {
+	for n := 1; n <= 7; n++ {
+		expected := newNQueensMatrix(n).Solve()
+		forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+			mat := newNQueensMatrix(n, backend)
+			assertSameSolutions(t, expected, mat.Solve())
+			assert.Equal(t, len(expected), mat.Count())
+		})
+	}
+}
+
+// the same encoding as in the nqueens package: rows and columns are primary, the diagonals secondary
+func newNQueensMatrix(n int, options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
+	for i := 0; i < 2*n; i++ {
+		_ = mat.AppendColumn(fmt.Sprintf("rc_%d", i))
+	}
+	for i := 0; i < 4*n-2; i++ {
+		_ = mat.AppendSecondaryColumn(fmt.Sprintf("d_%d", i))
+	}
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			row := make([]bool, 6*n-2)
+			row[r] = true
+			row[n+c] = true
+			row[2*n+r+c] = true
+			row[(4*n-1)+(n-r+c-1)] = true
+			_ = mat.AppendRow(fmt.Sprintf("queen_%d_%d", r, c), row)
+		}
+	}
+	return mat
+}
+
+func TestChooseBackend(t *testing.T) {
+	// 8 queens and 4x4 sudoku
+	assert.Equal(t, BitsetBackend, ChooseBackend(46, 4.0/46))
+	assert.Equal(t, BitsetBackend, ChooseBackend(64, 4.0/64))
+	// 9x9 sudoku
+	assert.Equal(t, LinkedListBackend, ChooseBackend(324, 4.0/324))
+	assert.Equal(t, LinkedListBackend, ChooseBackend(100, 0.01))
+}
+
+func TestWithShape(t *testing.T) {
+	assert.IsType(t, &BitsetMatrix{}, NewDancingLinkMatrix(WithShape(46, 4.0/46)))
+	assert.IsType(t, &DancingLinksMatrix{}, NewDancingLinkMatrix(WithShape(324, 4.0/324)))
+	assert.IsType(t, &DancingLinksMatrix{}, NewDancingLinkMatrix(WithShape(46, 4.0/46), WithBackend(LinkedListBackend)))
+	assert.IsType(t, &DancingCellsMatrix{}, NewDancingLinkMatrix(WithBackend(DancingCellsBackend), WithShape(46, 4.0/46)))
+
+	// the picked backend has to agree with the linked lists
+	expected := newNQueensMatrix(8).Solve()
+	mat := newNQueensMatrix(8, WithShape(46, 4.0/46))
+	assert.IsType(t, &BitsetMatrix{}, mat)
+	assertSameSolutions(t, expected, mat.Solve())
+}
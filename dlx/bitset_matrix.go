@@ -0,0 +1,206 @@
+package dlx
+
+import "math/bits"
+
+// BitsetMatrix implements Algorithm X on bitsets: every column knows its rows as a bitset and the available rows are
+// a single bitset, so the size of a column is the popcount of their intersection. For matrices with up to a few
+// hundred columns this is cheaper than chasing pointers.
+type BitsetMatrix struct {
+	matrix
+	rowColumnIndices [][]int // the sorted column indices of every row
+
+	// the bitsets below need to be rebuilt after the structure changed
+	dirty bool
+	// the rows of every column
+	columnRows [][]uint64
+	// the rows that are still available
+	availableRows []uint64
+	// the primary columns that are not covered yet
+	activeColumns []uint64
+	// the rows that were hidden when the column was covered, only valid while the column is covered
+	hiddenRows [][]uint64
+}
+
+func (m *BitsetMatrix) appendColumn(primary bool) {
+	m.dirty = true
+}
+
+func (m *BitsetMatrix) appendRow(columns []int) {
+	m.rowColumnIndices = append(m.rowColumnIndices, columns)
+	m.dirty = true
+}
+
+func (m *BitsetMatrix) removeRow(rowIndex int) {
+	m.rowColumnIndices = append(m.rowColumnIndices[:rowIndex], m.rowColumnIndices[rowIndex+1:]...)
+	m.dirty = true
+}
+
+func (m *BitsetMatrix) removeColumn(columnIndex int) {
+	// all columns after the removed one shift left by one
+	for r, columns := range m.rowColumnIndices {
+		remaining := columns[:0]
+		for _, c := range columns {
+			if c > columnIndex {
+				remaining = append(remaining, c-1)
+			} else if c < columnIndex {
+				remaining = append(remaining, c)
+			}
+		}
+		m.rowColumnIndices[r] = remaining
+	}
+	m.dirty = true
+}
+
+// build lays out the bitsets after the structure has changed, which is only possible while nothing is covered
+func (m *BitsetMatrix) build() {
+	if !m.dirty {
+		return
+	}
+
+	numWords := (len(m.rowColumnIndices) + 63) / 64
+	m.columnRows = make([][]uint64, len(m.primary))
+	m.hiddenRows = make([][]uint64, len(m.primary))
+	for c := range m.columnRows {
+		m.columnRows[c] = make([]uint64, numWords)
+		m.hiddenRows[c] = make([]uint64, numWords)
+	}
+	m.activeColumns = make([]uint64, (len(m.primary)+63)/64)
+	for c, primary := range m.primary {
+		if primary {
+			m.activeColumns[c/64] |= 1 << uint(c%64)
+		}
+	}
+	m.availableRows = make([]uint64, numWords)
+	for r, columns := range m.rowColumnIndices {
+		m.availableRows[r/64] |= 1 << uint(r%64)
+		for _, c := range columns {
+			m.columnRows[c][r/64] |= 1 << uint(r%64)
+		}
+	}
+
+	m.dirty = false
+}
+
+func (m *BitsetMatrix) cover(columnIndex int) {
+	// every available row of the column is hidden, we remember them to make them available again when uncovering
+	hidden := m.hiddenRows[columnIndex]
+	for i, word := range m.columnRows[columnIndex] {
+		hidden[i] = word & m.availableRows[i]
+		m.availableRows[i] &^= hidden[i]
+	}
+	m.activeColumns[columnIndex/64] &^= 1 << uint(columnIndex%64)
+	m.columnCovered[columnIndex] = true
+}
+
+func (m *BitsetMatrix) uncover(columnIndex int) {
+	for i, word := range m.hiddenRows[columnIndex] {
+		m.availableRows[i] |= word
+	}
+	if m.primary[columnIndex] {
+		m.activeColumns[columnIndex/64] |= 1 << uint(columnIndex%64)
+	}
+	m.columnCovered[columnIndex] = false
+}
+
+// returns the covered column that hid the row, -1 if the row is available
+func (m *BitsetMatrix) hiddenBy(rowIndex int) int {
+	for c, covered := range m.columnCovered {
+		if covered && m.hiddenRows[c][rowIndex/64]&(1<<uint(rowIndex%64)) != 0 {
+			return c
+		}
+	}
+	return -1
+}
+
+// asDenseMatrix matches the linked representation: a hidden row only remains visible in the column that hid it
+func (m *BitsetMatrix) asDenseMatrix() [][]bool {
+	denseMatrix := make([][]bool, len(m.rowColumnIndices))
+	for r, columns := range m.rowColumnIndices {
+		denseMatrix[r] = make([]bool, len(m.primary))
+		hiddenBy := m.hiddenBy(r)
+		for _, c := range columns {
+			if hiddenBy < 0 || hiddenBy == c {
+				denseMatrix[r][c] = true
+			}
+		}
+	}
+	return denseMatrix
+}
+
+func (m *BitsetMatrix) newEmpty() matrixBackend {
+	return newBitsetMatrix(m.config)
+}
+
+func (m *BitsetMatrix) replace(other matrixBackend) {
+	*m = *other.(*BitsetMatrix)
+	m.backend = m
+}
+
+func (m *BitsetMatrix) chooseColumn() int {
+	lowest := -1
+	lowestCount := 0
+	for i, word := range m.activeColumns {
+		for word != 0 {
+			c := i*64 + bits.TrailingZeros64(word)
+			word &= word - 1
+
+			cnt := 0
+			for j, rows := range m.columnRows[c] {
+				cnt += bits.OnesCount64(rows & m.availableRows[j])
+			}
+			if lowest < 0 || cnt < lowestCount {
+				// can't get any lower than that
+				if cnt == 0 {
+					return c
+				}
+				lowest = c
+				lowestCount = cnt
+			}
+		}
+	}
+	return lowest
+}
+
+func (m *BitsetMatrix) appendRows(rows []int, columnIndex int) []int {
+	// a covered column moved all of its available rows to the hidden ones
+	covered := m.columnCovered[columnIndex]
+	for i, word := range m.columnRows[columnIndex] {
+		if covered {
+			word = m.hiddenRows[columnIndex][i]
+		} else {
+			word &= m.availableRows[i]
+		}
+		for word != 0 {
+			rows = append(rows, i*64+bits.TrailingZeros64(word))
+			word &= word - 1
+		}
+	}
+	return rows
+}
+
+func (m *BitsetMatrix) rowColumns(rowIndex int) []int {
+	return m.rowColumnIndices[rowIndex]
+}
+
+func (m *BitsetMatrix) selectRow(rowIndex, columnIndex int) {
+	for _, c := range m.rowColumnIndices[rowIndex] {
+		if c != columnIndex {
+			m.cover(c)
+		}
+	}
+}
+
+func (m *BitsetMatrix) deselectRow(rowIndex, columnIndex int) {
+	columns := m.rowColumnIndices[rowIndex]
+	for i := len(columns) - 1; i >= 0; i-- {
+		if columns[i] != columnIndex {
+			m.uncover(columns[i])
+		}
+	}
+}
+
+func newBitsetMatrix(config searchConfig) *BitsetMatrix {
+	m := &BitsetMatrix{}
+	m.matrix = newMatrix(m, config)
+	return m
+}
@@ -0,0 +1,184 @@
+package dlx
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CNFEncoding selects how the exactly-one and at-most-one constraints of the columns are written as clauses
+type CNFEncoding int
+
+const (
+	// every pair of rows in a column gets a clause, no auxiliary variables but quadratically many clauses
+	PairwiseEncoding CNFEncoding = iota
+	// Sinz' sequential counter, linearly many clauses with one auxiliary variable per row of a column
+	SequentialCounterEncoding
+	// Klieber and Kwon's commander encoding, groups of three rows are represented by a commander variable recursively
+	CommanderEncoding
+)
+
+// the number of rows that share a commander variable
+const commanderGroupSize = 3
+
+func (e CNFEncoding) String() string {
+	switch e {
+	case PairwiseEncoding:
+		return "Pairwise"
+	case SequentialCounterEncoding:
+		return "SequentialCounter"
+	case CommanderEncoding:
+		return "Commander"
+	default:
+		return "Unknown"
+	}
+}
+
+type cnfWriter struct {
+	encoding CNFEncoding
+	numVars  int
+	clauses  [][]int
+}
+
+// exportCNF writes the exact cover problem in DIMACS CNF, row i becomes variable i+1.
+// The rows forced by the assumptions become unit clauses. The columns covered by CoverColumn are done, their rows
+// can't be chosen anymore and become negated unit clauses.
+func exportCNF(backend coverBackend, a *assumptions, writer io.Writer, encoding CNFEncoding) (map[int]string, error) {
+	if encoding < PairwiseEncoding || encoding > CommanderEncoding {
+		return nil, fmt.Errorf("unknown cnf encoding %d", encoding)
+	}
+
+	rows := backend.Rows()
+	columns := backend.Columns()
+	w := &cnfWriter{encoding: encoding, numVars: len(rows)}
+
+	columnRows := make([][]int, len(columns))
+	variables := make(map[int]string, len(rows))
+	for r, row := range rows {
+		variables[r+1] = row
+		for _, c := range backend.rowColumns(r) {
+			columnRows[c] = append(columnRows[c], r+1)
+		}
+	}
+
+	// the columns of the forced rows are covered as well, but the unit clauses of the rows already take care of them
+	assumed := map[int]bool{}
+	for _, frame := range a.frames {
+		for _, c := range frame.columns {
+			assumed[c] = true
+		}
+	}
+
+	for c, vars := range columnRows {
+		if backend.isCovered(c) && !assumed[c] {
+			for _, v := range vars {
+				w.addClause(-v)
+			}
+			continue
+		}
+		if backend.isPrimary(c) {
+			w.addClause(vars...)
+		}
+		w.atMostOne(vars)
+	}
+
+	for _, frame := range a.frames {
+		for _, r := range frame.rows {
+			w.addClause(r + 1)
+		}
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(fmt.Sprintf("c exact cover with %d rows and %d columns, %s encoding\n",
+		len(rows), len(columns), encoding))
+	sb.WriteString(fmt.Sprintf("c variables 1 to %d are the rows in matrix order\n", len(rows)))
+	sb.WriteString(fmt.Sprintf("p cnf %d %d\n", w.numVars, len(w.clauses)))
+	for _, clause := range w.clauses {
+		for _, literal := range clause {
+			sb.WriteString(strconv.Itoa(literal))
+			sb.WriteString(" ")
+		}
+		sb.WriteString("0\n")
+	}
+
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		return nil, err
+	}
+	return variables, nil
+}
+
+func (w *cnfWriter) addClause(literals ...int) {
+	w.clauses = append(w.clauses, literals)
+}
+
+func (w *cnfWriter) newVariable() int {
+	w.numVars++
+	return w.numVars
+}
+
+func (w *cnfWriter) atMostOne(vars []int) {
+	switch w.encoding {
+	case SequentialCounterEncoding:
+		w.sequentialCounter(vars)
+	case CommanderEncoding:
+		w.commander(vars)
+	default:
+		w.pairwise(vars)
+	}
+}
+
+func (w *cnfWriter) pairwise(vars []int) {
+	for i := 0; i < len(vars); i++ {
+		for j := i + 1; j < len(vars); j++ {
+			w.addClause(-vars[i], -vars[j])
+		}
+	}
+}
+
+// the auxiliary variable s_i is true when any of the first i+1 rows is chosen
+func (w *cnfWriter) sequentialCounter(vars []int) {
+	if len(vars) <= 1 {
+		return
+	}
+
+	n := len(vars)
+	s := make([]int, n-1)
+	for i := range s {
+		s[i] = w.newVariable()
+	}
+
+	w.addClause(-vars[0], s[0])
+	for i := 1; i < n-1; i++ {
+		w.addClause(-vars[i], s[i])
+		w.addClause(-s[i-1], s[i])
+		w.addClause(-vars[i], -s[i-1])
+	}
+	w.addClause(-vars[n-1], -s[n-2])
+}
+
+// the commander of a group is true exactly when one of its rows is chosen, at most one commander can be true
+func (w *cnfWriter) commander(vars []int) {
+	if len(vars) <= commanderGroupSize {
+		w.pairwise(vars)
+		return
+	}
+
+	var commanders []int
+	for start := 0; start < len(vars); start += commanderGroupSize {
+		end := start + commanderGroupSize
+		if end > len(vars) {
+			end = len(vars)
+		}
+		group := vars[start:end]
+		c := w.newVariable()
+		commanders = append(commanders, c)
+
+		w.pairwise(group)
+		w.addClause(append([]int{-c}, group...)...)
+		for _, v := range group {
+			w.addClause(-v, c)
+		}
+	}
+	w.commander(commanders)
+}
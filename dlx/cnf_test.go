@@ -0,0 +1,209 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var allEncodings = []CNFEncoding{PairwiseEncoding, SequentialCounterEncoding, CommanderEncoding}
+
+func TestExportCNFPairwise(t *testing.T) {
+	mat := NewReadMeExample()
+	sb := &strings.Builder{}
+	variables, err := mat.ExportCNF(sb, PairwiseEncoding)
+	assert.Nil(t, err)
+	assert.Equal(t, map[int]string{1: "Jack", 2: "Amanda", 3: "Chris", 4: "Jen"}, variables)
+	assert.Equal(t, "c exact cover with 4 rows and 3 columns, Pairwise encoding\n"+
+		"c variables 1 to 4 are the rows in matrix order\n"+
+		"p cnf 4 8\n"+
+		"1 2 4 0\n"+
+		"-1 -2 0\n"+
+		"-1 -4 0\n"+
+		"-2 -4 0\n"+
+		"2 4 0\n"+
+		"-2 -4 0\n"+
+		"3 4 0\n"+
+		"-3 -4 0\n", sb.String())
+}
+
+func TestExportCNFSolutionsMatch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		for _, encoding := range allEncodings {
+			for _, mat := range []DancingLinksMatrixI{
+				NewReadMeExample(backend),
+				NewWikipediaExampleMatrix(t, backend),
+				newNQueensMatrix(3, backend),
+				newNQueensMatrix(4, backend),
+				newOneOfManyMatrix(t, 8, backend),
+			} {
+				sb := &strings.Builder{}
+				variables, err := mat.ExportCNF(sb, encoding)
+				assert.Nil(t, err)
+				assertSameSolutions(t, mat.Solve(), solveCNF(t, sb.String(), variables))
+			}
+		}
+	})
+}
+
+func TestExportCNFAuxiliaryVariables(t *testing.T) {
+	expectedVars := map[CNFEncoding]int{
+		PairwiseEncoding: 8,
+		// one per row but the last
+		SequentialCounterEncoding: 8 + 7,
+		// three groups and the commanders fit into a single group
+		CommanderEncoding: 8 + 3,
+	}
+	for _, encoding := range allEncodings {
+		sb := &strings.Builder{}
+		_, err := newOneOfManyMatrix(t, 8, WithBackend(LinkedListBackend)).ExportCNF(sb, encoding)
+		assert.Nil(t, err)
+		numVars, _ := parseCNF(t, sb.String())
+		assert.Equal(t, expectedVars[encoding], numVars, encoding.String())
+	}
+}
+
+func TestExportCNFAssumptions(t *testing.T) {
+	mat := NewReadMeExample()
+	assert.Nil(t, mat.Push("Chris"))
+	sb := &strings.Builder{}
+	variables, err := mat.ExportCNF(sb, PairwiseEncoding)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasSuffix(sb.String(), "3 0\n"))
+	assertSameSolutions(t, [][]string{{"Amanda", "Chris"}}, solveCNF(t, sb.String(), variables))
+}
+
+func TestExportCNFCoveredColumns(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.CoverColumnByName("sour cream"))
+		for _, encoding := range []CNFEncoding{PairwiseEncoding, SequentialCounterEncoding, CommanderEncoding} {
+			sb := &strings.Builder{}
+			variables, err := mat.ExportCNF(sb, encoding)
+			assert.Nil(t, err)
+			assertSameSolutions(t, mat.Solve(), solveCNF(t, sb.String(), variables))
+		}
+
+		assert.Nil(t, mat.Push("Amanda"))
+		sb := &strings.Builder{}
+		variables, err := mat.ExportCNF(sb, PairwiseEncoding)
+		assert.Nil(t, err)
+		assertSameSolutions(t, [][]string{{"Amanda"}}, solveCNF(t, sb.String(), variables))
+		assert.Equal(t, [][]string{{"Amanda"}}, mat.Solve())
+	})
+}
+
+func TestExportCNFUnknownEncoding(t *testing.T) {
+	_, err := NewReadMeExample().ExportCNF(&strings.Builder{}, CNFEncoding(42))
+	assert.EqualError(t, err, "unknown cnf encoding 42")
+}
+
+// a single primary column that all rows share, every row has its own secondary column
+func newOneOfManyMatrix(t *testing.T, n int, options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
+	assert.Nil(t, mat.AppendColumn("shared"))
+	for i := 0; i < n; i++ {
+		assert.Nil(t, mat.AppendSecondaryColumn(fmt.Sprintf("own_%d", i)))
+	}
+	for i := 0; i < n; i++ {
+		row := make([]bool, n+1)
+		row[0] = true
+		row[i+1] = true
+		assert.Nil(t, mat.AppendRow(fmt.Sprintf("%d", i), row))
+	}
+	return mat
+}
+
+func parseCNF(t *testing.T, cnf string) (int, [][]int) {
+	numVars := 0
+	var clauses [][]int
+	for _, line := range strings.Split(strings.TrimSpace(cnf), "\n") {
+		if strings.HasPrefix(line, "c ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if fields[0] == "p" {
+			n, err := strconv.Atoi(fields[2])
+			assert.Nil(t, err)
+			numVars = n
+			continue
+		}
+		var clause []int
+		for _, f := range fields[:len(fields)-1] {
+			literal, err := strconv.Atoi(f)
+			assert.Nil(t, err)
+			clause = append(clause, literal)
+		}
+		clauses = append(clauses, clause)
+	}
+	return numVars, clauses
+}
+
+// solveCNF brute forces all row assignments and keeps those that the auxiliary variables can satisfy
+func solveCNF(t *testing.T, cnf string, variables map[int]string) [][]string {
+	numVars, clauses := parseCNF(t, cnf)
+	numRows := len(variables)
+	assert.True(t, numRows <= 16, "too many rows to brute force: %d", numRows)
+
+	var solutions [][]string
+	for rowAssignment := 0; rowAssignment < 1<<uint(numRows); rowAssignment++ {
+		values := make([]int, numVars+1)
+		for v := 1; v <= numRows; v++ {
+			values[v] = -1
+			if rowAssignment&(1<<uint(v-1)) != 0 {
+				values[v] = 1
+			}
+		}
+		if !satisfiable(clauses, values) {
+			continue
+		}
+
+		var rows []string
+		for v := 1; v <= numRows; v++ {
+			if values[v] > 0 {
+				rows = append(rows, variables[v])
+			}
+		}
+		solutions = append(solutions, rows)
+	}
+	return solutions
+}
+
+// a plain backtracking search over the unassigned variables, values are 1 (true), -1 (false) or 0 (unassigned)
+func satisfiable(clauses [][]int, values []int) bool {
+	for _, clause := range clauses {
+		satisfied := false
+		unassigned := 0
+		for _, literal := range clause {
+			v, sign := literal, 1
+			if v < 0 {
+				v, sign = -v, -1
+			}
+			if values[v] == sign {
+				satisfied = true
+				break
+			}
+			if values[v] == 0 && unassigned == 0 {
+				unassigned = v
+			}
+		}
+		if satisfied {
+			continue
+		}
+		if unassigned == 0 {
+			return false
+		}
+		for _, value := range []int{1, -1} {
+			values[unassigned] = value
+			if satisfiable(clauses, values) {
+				values[unassigned] = 0
+				return true
+			}
+		}
+		values[unassigned] = 0
+		return false
+	}
+	return true
+}
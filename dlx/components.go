@@ -0,0 +1,239 @@
+package dlx
+
+// components groups the uncovered columns that are connected through available rows. Only the groups that contain a
+// primary column are returned, the others never need to be covered. The columns of a group are sorted by index and
+// the groups by their first column.
+func components(backend coverBackend) [][]int {
+	numColumns := len(backend.Columns())
+	parent := make([]int, numColumns)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(c int) int {
+		for parent[c] != c {
+			parent[c] = parent[parent[c]]
+			c = parent[c]
+		}
+		return c
+	}
+
+	// an available row never touches a covered column, so connecting every column to the first column of each of
+	// its rows connects all columns of a row
+	var rows []int
+	for c := 0; c < numColumns; c++ {
+		if backend.isCovered(c) {
+			continue
+		}
+		rows = backend.appendRows(rows[:0], c)
+		for _, r := range rows {
+			parent[find(c)] = find(backend.rowColumns(r)[0])
+		}
+	}
+
+	groupIndex := map[int]int{}
+	var groups [][]int
+	var primary []bool
+	for c := 0; c < numColumns; c++ {
+		if backend.isCovered(c) {
+			continue
+		}
+		root := find(c)
+		i, ok := groupIndex[root]
+		if !ok {
+			i = len(groups)
+			groupIndex[root] = i
+			groups = append(groups, nil)
+			primary = append(primary, false)
+		}
+		groups[i] = append(groups[i], c)
+		primary[i] = primary[i] || backend.isPrimary(c)
+	}
+
+	var result [][]int
+	for i, group := range groups {
+		if primary[i] {
+			result = append(result, group)
+		}
+	}
+	return result
+}
+
+// componentIdentifiers maps the column indices of the components to their names
+func componentIdentifiers(backend coverBackend) [][]string {
+	columns := backend.Columns()
+	var result [][]string
+	for _, group := range components(backend) {
+		names := make([]string, len(group))
+		for i, c := range group {
+			names[i] = columns[c]
+		}
+		result = append(result, names)
+	}
+	return result
+}
+
+// decomposer solves the independent components of the matrix separately
+type decomposer struct {
+	backend coverBackend
+	// the searches within the components report to the tracer
+	tracer Tracer
+	// whether the components are detected again after every chosen row, otherwise only once at the start
+	duringSearch bool
+	// the maximum number of solutions collected per component, zero collects all of them
+	limit int
+	// orders the rows of the chosen columns, nil keeps them in the order of the backend
+	orderer RowOrderer
+	ranks   *rowRanks
+}
+
+// isolate covers all uncovered columns outside of the group, so the search only sees the group
+func (d *decomposer) isolate(group []int, f func()) {
+	inGroup := make([]bool, len(d.backend.Columns()))
+	for _, c := range group {
+		inGroup[c] = true
+	}
+
+	var covered []int
+	for c := range inGroup {
+		if !inGroup[c] && !d.backend.isCovered(c) {
+			d.backend.cover(c)
+			covered = append(covered, c)
+		}
+	}
+
+	f()
+
+	for i := len(covered) - 1; i >= 0; i-- {
+		d.backend.uncover(covered[i])
+	}
+}
+
+// count multiplies the counts of the components
+func (d *decomposer) count() int {
+	groups := components(d.backend)
+	if len(groups) <= 1 {
+		return d.countComponent()
+	}
+
+	total := 1
+	for _, group := range groups {
+		n := 0
+		d.isolate(group, func() {
+			n = d.countComponent()
+		})
+		// no need to look at the other components anymore
+		if n == 0 {
+			return 0
+		}
+		total *= n
+	}
+	return total
+}
+
+func (d *decomposer) countComponent() int {
+	if !d.duringSearch {
+		n := 0
+		search(d.backend, nil, d.tracer, nil, nil, func(solution []int) bool {
+			n++
+			return true
+		})
+		return n
+	}
+
+	column := d.backend.chooseColumn()
+	if column < 0 {
+		return 1
+	}
+
+	n := 0
+	d.backend.cover(column)
+	for _, row := range d.backend.appendRows(nil, column) {
+		d.backend.selectRow(row, column)
+		n += d.count()
+		d.backend.deselectRow(row, column)
+	}
+	d.backend.uncover(column)
+	return n
+}
+
+// enumerate collects the solutions of every component and hands their combinations to the visitor lazily
+func (d *decomposer) enumerate(partialSolution []int, visitor func(solution []int) bool) bool {
+	groups := components(d.backend)
+	if len(groups) <= 1 {
+		return d.enumerateComponent(partialSolution, visitor)
+	}
+
+	solutions := make([][][]int, len(groups))
+	for i, group := range groups {
+		d.isolate(group, func() {
+			d.enumerateComponent(nil, func(solution []int) bool {
+				c := make([]int, len(solution))
+				copy(c, solution)
+				solutions[i] = append(solutions[i], c)
+				return d.limit <= 0 || len(solutions[i]) < d.limit
+			})
+		})
+		// without a solution for this component there's nothing to combine
+		if len(solutions[i]) == 0 {
+			return true
+		}
+	}
+	return combine(solutions, partialSolution, visitor)
+}
+
+func (d *decomposer) enumerateComponent(partialSolution []int, visitor func(solution []int) bool) bool {
+	if !d.duringSearch {
+		return search(d.backend, nil, d.tracer, d.orderer, partialSolution, visitor)
+	}
+
+	column := d.backend.chooseColumn()
+	if column < 0 {
+		return visitor(partialSolution)
+	}
+
+	proceed := true
+	d.backend.cover(column)
+	rows := d.backend.appendRows(nil, column)
+	if d.orderer != nil {
+		if d.ranks == nil {
+			d.ranks = newRowRanks(d.backend, d.orderer)
+		}
+		d.ranks.sort(rows, column)
+	}
+	for i := 0; proceed && i < len(rows); i++ {
+		partialSolution = append(partialSolution, rows[i])
+		d.backend.selectRow(rows[i], column)
+		proceed = d.enumerate(partialSolution, visitor)
+		d.backend.deselectRow(rows[i], column)
+		partialSolution = partialSolution[:len(partialSolution)-1]
+	}
+	d.backend.uncover(column)
+	return proceed
+}
+
+// combine hands every combination of one solution per component to the visitor, appended to the partial solution
+func combine(solutions [][][]int, partialSolution []int, visitor func(solution []int) bool) bool {
+	indices := make([]int, len(solutions))
+	for {
+		combined := partialSolution
+		for i, s := range solutions {
+			combined = append(combined, s[indices[i]]...)
+		}
+		if !visitor(combined) {
+			return false
+		}
+
+		// advance the last component first, like an odometer
+		i := len(indices) - 1
+		for ; i >= 0; i-- {
+			indices[i]++
+			if indices[i] < len(solutions[i]) {
+				break
+			}
+			indices[i] = 0
+		}
+		if i < 0 {
+			return true
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+var allDecompositions = []Decomposition{DecomposeUpFront, DecomposeDuringSearch}
+
+func TestComponents(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		assert.Equal(t, [][]string{{"beer", "nachos", "sour cream"}}, NewReadMeExample(backend).Components())
+
+		mat := newDisjointMatrix(t, 3, backend)
+		assert.Equal(t, [][]string{{"0_0", "0_1", "0_2"}, {"1_0", "1_1", "1_2"}, {"2_0", "2_1", "2_2"}}, mat.Components())
+
+		// choosing row A of the first copy covers all of its columns
+		assert.Nil(t, mat.Push("0_A"))
+		assert.Equal(t, [][]string{{"1_0", "1_1", "1_2"}, {"2_0", "2_1", "2_2"}}, mat.Components())
+		assert.Nil(t, mat.Pop())
+	})
+}
+
+func TestComponentsLeaveOutSecondaryOnlyColumns(t *testing.T) {
+	mat := NewDancingLinkMatrix()
+	assert.Nil(t, mat.AppendColumn("a"))
+	assert.Nil(t, mat.AppendSecondaryColumn("b"))
+	assert.Nil(t, mat.AppendSecondaryColumn("c"))
+	assert.Nil(t, mat.AppendColumn("d"))
+	assert.Nil(t, mat.AppendRow("1", []bool{true, false, false, false}))
+	assert.Nil(t, mat.AppendRow("2", []bool{false, true, true, false}))
+	assert.Equal(t, [][]string{{"a"}, {"d"}}, mat.Components())
+}
+
+func TestDecompositionFindsTheSameSolutions(t *testing.T) {
+	newMatrices := []func(options ...MatrixOption) DancingLinksMatrixI{
+		func(options ...MatrixOption) DancingLinksMatrixI { return NewReadMeExample(options...) },
+		func(options ...MatrixOption) DancingLinksMatrixI { return NewWikipediaExampleMatrix(t, options...) },
+		func(options ...MatrixOption) DancingLinksMatrixI { return newNQueensMatrix(6, options...) },
+		func(options ...MatrixOption) DancingLinksMatrixI { return newDisjointMatrix(t, 4, options...) },
+	}
+	for _, decomposition := range allDecompositions {
+		forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+			for _, newMatrix := range newMatrices {
+				expected := newMatrix().Solve()
+				mat := newMatrix(backend, WithDecomposition(decomposition))
+				assertSameSolutions(t, expected, mat.Solve())
+				assert.Equal(t, len(expected), mat.Count())
+				assert.Contains(t, normalizeSolutions(expected), normalizeSolutions([][]string{mat.SolveOne()})[0])
+			}
+		})
+	}
+}
+
+func TestDecompositionMultipliesCounts(t *testing.T) {
+	for _, decomposition := range allDecompositions {
+		forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+			// exploring the product would visit 3^15 solutions
+			mat := newDisjointMatrix(t, 15, backend, WithDecomposition(decomposition))
+			expected := mat.AsDenseMatrix()
+			assert.Equal(t, 14348907, mat.Count())
+			assert.Equal(t, 15, len(mat.SolveOne()))
+			assert.Equal(t, expected, mat.AsDenseMatrix())
+
+			assert.Nil(t, mat.AppendColumn("chips"))
+			assert.Equal(t, 0, mat.Count())
+			assert.Nil(t, mat.SolveOne())
+		})
+	}
+}
+
+func TestDecompositionWithAssumptions(t *testing.T) {
+	for _, decomposition := range allDecompositions {
+		mat := newDisjointMatrix(t, 2, WithDecomposition(decomposition))
+		assert.Nil(t, mat.Push("0_A"))
+		assertSameSolutions(t, [][]string{{"0_A", "1_A"}, {"0_A", "1_B", "1_C"}, {"0_A", "1_D", "1_E"}}, mat.Solve())
+		assert.Equal(t, "0_A", mat.SolveOne()[0])
+		assert.Equal(t, 3, mat.Count())
+	}
+}
+
+// copies of the matrix with the three solutions A, BC and DE that don't share any columns
+func newDisjointMatrix(t *testing.T, copies int, options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
+	for i := 0; i < copies; i++ {
+		for c := 0; c < 3; c++ {
+			assert.Nil(t, mat.AppendColumn(fmt.Sprintf("%d_%d", i, c)))
+		}
+	}
+	rows := map[string][]bool{
+		"A": {true, true, true},
+		"B": {true, false, true},
+		"C": {false, true, false},
+		"D": {true, true, false},
+		"E": {false, false, true},
+	}
+	for i := 0; i < copies; i++ {
+		for _, name := range []string{"A", "B", "C", "D", "E"} {
+			row := make([]bool, 3*copies)
+			copy(row[3*i:], rows[name])
+			assert.Nil(t, mat.AppendRow(fmt.Sprintf("%d_%s", i, name), row))
+		}
+	}
+	return mat
+}
@@ -0,0 +1,224 @@
+package dlx
+
+// DancingCellsMatrix implements Knuth's dancing cells: instead of linked nodes, the uncovered primary items and the
+// available options (rows) of every item are kept as sparse sets in flat arrays. Removing an element swaps it behind
+// the active part of its set and restoring it only needs to swap it back in.
+type DancingCellsMatrix struct {
+	matrix
+	rowColumnIndices [][]int // the sorted column indices of every row
+	// the covered column that hid the row, -1 if the row is available
+	hiddenBy []int
+
+	// the sparse sets below need to be rebuilt after the structure changed
+	dirty bool
+	// the uncovered primary items, the first numActive are active
+	activeItems []int
+	itemPos     []int
+	numActive   int
+	// the options of every item, the first itemSize[c] options of itemCells[c] are available
+	itemCells [][]cell
+	itemSize  []int
+	// the position of every cell of a row in itemCells, indexed like rowColumnIndices
+	cellPos [][]int
+}
+
+// cell is the occurrence of a row in an item, slot is the index of the item in the columns of that row
+type cell struct {
+	row  int
+	slot int
+}
+
+func (m *DancingCellsMatrix) appendColumn(primary bool) {
+	m.dirty = true
+}
+
+func (m *DancingCellsMatrix) appendRow(columns []int) {
+	m.rowColumnIndices = append(m.rowColumnIndices, columns)
+	m.hiddenBy = append(m.hiddenBy, -1)
+	m.dirty = true
+}
+
+func (m *DancingCellsMatrix) removeRow(rowIndex int) {
+	m.rowColumnIndices = append(m.rowColumnIndices[:rowIndex], m.rowColumnIndices[rowIndex+1:]...)
+	m.hiddenBy = append(m.hiddenBy[:rowIndex], m.hiddenBy[rowIndex+1:]...)
+	m.dirty = true
+}
+
+func (m *DancingCellsMatrix) removeColumn(columnIndex int) {
+	// all columns after the removed one shift left by one
+	for r, columns := range m.rowColumnIndices {
+		remaining := columns[:0]
+		for _, c := range columns {
+			if c > columnIndex {
+				remaining = append(remaining, c-1)
+			} else if c < columnIndex {
+				remaining = append(remaining, c)
+			}
+		}
+		m.rowColumnIndices[r] = remaining
+	}
+	m.dirty = true
+}
+
+// build lays out the sparse sets after the structure has changed, which is only possible while nothing is covered
+func (m *DancingCellsMatrix) build() {
+	if !m.dirty {
+		return
+	}
+
+	numColumns := len(m.primary)
+	m.activeItems = make([]int, 0, numColumns)
+	m.itemPos = make([]int, numColumns)
+	for c := 0; c < numColumns; c++ {
+		m.itemPos[c] = -1
+		if m.primary[c] {
+			m.itemPos[c] = len(m.activeItems)
+			m.activeItems = append(m.activeItems, c)
+		}
+	}
+	m.numActive = len(m.activeItems)
+
+	m.itemCells = make([][]cell, numColumns)
+	m.itemSize = make([]int, numColumns)
+	m.cellPos = make([][]int, len(m.rowColumnIndices))
+	for r, columns := range m.rowColumnIndices {
+		m.cellPos[r] = make([]int, len(columns))
+		for slot, c := range columns {
+			m.cellPos[r][slot] = len(m.itemCells[c])
+			m.itemCells[c] = append(m.itemCells[c], cell{row: r, slot: slot})
+		}
+	}
+	for c := range m.itemCells {
+		m.itemSize[c] = len(m.itemCells[c])
+	}
+
+	m.dirty = false
+}
+
+func (m *DancingCellsMatrix) cover(columnIndex int) {
+	if m.primary[columnIndex] {
+		m.swapItem(m.itemPos[columnIndex], m.numActive-1)
+		m.numActive--
+	}
+
+	// hide all available rows of the column from their other items
+	cells := m.itemCells[columnIndex]
+	for i := 0; i < m.itemSize[columnIndex]; i++ {
+		row := cells[i].row
+		for slot, c := range m.rowColumnIndices[row] {
+			// covered items are never looked at before they're uncovered again, so we don't need to maintain them
+			if c == columnIndex || m.columnCovered[c] {
+				continue
+			}
+			m.swapCell(c, m.cellPos[row][slot], m.itemSize[c]-1)
+			m.itemSize[c]--
+		}
+		m.hiddenBy[row] = columnIndex
+	}
+
+	m.columnCovered[columnIndex] = true
+}
+
+func (m *DancingCellsMatrix) uncover(columnIndex int) {
+	m.columnCovered[columnIndex] = false
+
+	cells := m.itemCells[columnIndex]
+	for i := m.itemSize[columnIndex] - 1; i >= 0; i-- {
+		row := cells[i].row
+		columns := m.rowColumnIndices[row]
+		for slot := len(columns) - 1; slot >= 0; slot-- {
+			c := columns[slot]
+			if c == columnIndex || m.columnCovered[c] {
+				continue
+			}
+			m.swapCell(c, m.cellPos[row][slot], m.itemSize[c])
+			m.itemSize[c]++
+		}
+		m.hiddenBy[row] = -1
+	}
+
+	if m.primary[columnIndex] {
+		m.swapItem(m.itemPos[columnIndex], m.numActive)
+		m.numActive++
+	}
+}
+
+func (m *DancingCellsMatrix) swapItem(i, j int) {
+	a, b := m.activeItems[i], m.activeItems[j]
+	m.activeItems[i], m.activeItems[j] = b, a
+	m.itemPos[a], m.itemPos[b] = j, i
+}
+
+func (m *DancingCellsMatrix) swapCell(columnIndex, i, j int) {
+	cells := m.itemCells[columnIndex]
+	a, b := cells[i], cells[j]
+	cells[i], cells[j] = b, a
+	m.cellPos[a.row][a.slot], m.cellPos[b.row][b.slot] = j, i
+}
+
+// asDenseMatrix matches the linked representation: a hidden row only remains visible in the column that hid it
+func (m *DancingCellsMatrix) asDenseMatrix() [][]bool {
+	denseMatrix := make([][]bool, len(m.rowColumnIndices))
+	for r, columns := range m.rowColumnIndices {
+		denseMatrix[r] = make([]bool, len(m.primary))
+		for _, c := range columns {
+			if m.hiddenBy[r] < 0 || m.hiddenBy[r] == c {
+				denseMatrix[r][c] = true
+			}
+		}
+	}
+	return denseMatrix
+}
+
+func (m *DancingCellsMatrix) newEmpty() matrixBackend {
+	return newDancingCellsMatrix(m.config)
+}
+
+func (m *DancingCellsMatrix) replace(other matrixBackend) {
+	*m = *other.(*DancingCellsMatrix)
+	m.backend = m
+}
+
+func (m *DancingCellsMatrix) chooseColumn() int {
+	lowest := -1
+	for _, c := range m.activeItems[:m.numActive] {
+		if lowest < 0 || m.itemSize[c] < m.itemSize[lowest] || (m.itemSize[c] == m.itemSize[lowest] && c < lowest) {
+			lowest = c
+		}
+	}
+	return lowest
+}
+
+func (m *DancingCellsMatrix) appendRows(rows []int, columnIndex int) []int {
+	for _, cell := range m.itemCells[columnIndex][:m.itemSize[columnIndex]] {
+		rows = append(rows, cell.row)
+	}
+	return rows
+}
+
+func (m *DancingCellsMatrix) rowColumns(rowIndex int) []int {
+	return m.rowColumnIndices[rowIndex]
+}
+
+func (m *DancingCellsMatrix) selectRow(rowIndex, columnIndex int) {
+	for _, c := range m.rowColumnIndices[rowIndex] {
+		if c != columnIndex {
+			m.cover(c)
+		}
+	}
+}
+
+func (m *DancingCellsMatrix) deselectRow(rowIndex, columnIndex int) {
+	columns := m.rowColumnIndices[rowIndex]
+	for i := len(columns) - 1; i >= 0; i-- {
+		if columns[i] != columnIndex {
+			m.uncover(columns[i])
+		}
+	}
+}
+
+func newDancingCellsMatrix(config searchConfig) *DancingCellsMatrix {
+	m := &DancingCellsMatrix{}
+	m.matrix = newMatrix(m, config)
+	return m
+}
@@ -2,16 +2,17 @@ package dlx
 
 import (
 	"fmt"
+	"io"
 	"math"
+	"strings"
 )
 
 type DancingLinksMatrix struct {
-	columnCovered     []bool
+	matrix
 	numNodesPerColumn []int
-	columnIdentifiers []string
-	rowIdentifiers    []string
 	columnNodes       []*Node
-	head              *Node // top-left corner "head" of the matrix
+	rowNodes          []*Node // first node of every row, nil if the row is empty
+	head              *Node   // top-left corner "head" of the matrix
 }
 
 type Node struct {
@@ -25,16 +26,9 @@ type Node struct {
 	colIndex int
 }
 
-func (m *DancingLinksMatrix) AppendColumn(columnIdentifier string) {
-	m.appendColumnInternally(columnIdentifier, true)
-}
-
-func (m *DancingLinksMatrix) AppendSecondaryColumn(columnIdentifier string) {
-	m.appendColumnInternally(columnIdentifier, false)
-}
-
-func (m *DancingLinksMatrix) appendColumnInternally(columnIdentifier string, primary bool) {
-	newCol := &Node{colIndex: len(m.columnIdentifiers)}
+func (m *DancingLinksMatrix) appendColumn(primary bool) {
+	// the existing rows stay as they are, which means they're all false in the new column
+	newCol := &Node{colIndex: len(m.columnNodes)}
 	newCol.top = newCol
 	newCol.bottom = newCol
 
@@ -51,59 +45,117 @@ func (m *DancingLinksMatrix) appendColumnInternally(columnIdentifier string, pri
 	}
 
 	// make sure we track the column values properly
-	m.columnIdentifiers = append(m.columnIdentifiers, columnIdentifier)
 	m.columnNodes = append(m.columnNodes, newCol)
-	m.columnCovered = append(m.columnCovered, false)
 	m.numNodesPerColumn = append(m.numNodesPerColumn, 0)
 }
 
-func (m *DancingLinksMatrix) AppendRow(rowIdentifier string, rowValues []bool) error {
-	if len(rowValues) != len(m.columnIdentifiers) {
-		return fmt.Errorf("column mismatch: have only %d columns registered, but got %d",
-			len(m.columnIdentifiers), len(rowValues))
+func (m *DancingLinksMatrix) appendRow(columns []int) {
+	numRows := len(m.rowNodes)
+
+	var first *Node
+	var last *Node
+	for _, i := range columns {
+		colTop := m.columnNodes[i]
+		m.numNodesPerColumn[i]++
+		bottom := colTop.top
+		node := &Node{top: bottom, bottom: colTop, colIndex: i, rowIndex: numRows}
+		bottom.bottom = node
+		colTop.top = node
+
+		if last != nil {
+			rowHead := last.right
+			node.left = last
+			node.right = rowHead
+			last.right = node
+			rowHead.left = node
+		} else {
+			node.left = node
+			node.right = node
+			first = node
+		}
+
+		last = node
 	}
 
-	numRows := len(m.rowIdentifiers)
+	m.rowNodes = append(m.rowNodes, first)
+}
 
-	var last *Node
-	for i := 0; i < len(rowValues); i++ {
-		// since this models a sparse matrix, we're only interested in true values
-		if rowValues[i] {
-			colTop := m.columnNodes[i]
-			m.numNodesPerColumn[i]++
-			bottom := colTop.top
-			node := &Node{top: bottom, bottom: colTop, colIndex: i, rowIndex: numRows}
-			bottom.bottom = node
-			colTop.top = node
-
-			if last != nil {
-				rowHead := last.right
-				node.left = last
-				node.right = rowHead
-				last.right = node
-				rowHead.left = node
-			} else {
-				node.left = node
-				node.right = node
+func (m *DancingLinksMatrix) removeRow(rowIndex int) {
+	// unlink all nodes of the row from their columns
+	first := m.rowNodes[rowIndex]
+	if first != nil {
+		node := first
+		for {
+			node.bottom.top = node.top
+			node.top.bottom = node.bottom
+			m.numNodesPerColumn[node.colIndex]--
+			node = node.right
+			if node == first {
+				break
 			}
-
-			last = node
 		}
 	}
 
-	m.rowIdentifiers = append(m.rowIdentifiers, rowIdentifier)
-	return nil
+	m.rowNodes = append(m.rowNodes[:rowIndex], m.rowNodes[rowIndex+1:]...)
+
+	// all rows after the removed one shift up by one
+	for i := rowIndex; i < len(m.rowNodes); i++ {
+		first := m.rowNodes[i]
+		if first == nil {
+			continue
+		}
+		node := first
+		for {
+			node.rowIndex = i
+			node = node.right
+			if node == first {
+				break
+			}
+		}
+	}
 }
 
-func (m *DancingLinksMatrix) CoverColumn(columnIndex int) error {
-	if columnIndex < 0 || columnIndex >= len(m.columnCovered) {
-		return fmt.Errorf("column at index %d does not exist", columnIndex)
+func (m *DancingLinksMatrix) removeColumn(columnIndex int) {
+	// unlink the header, this is a no-op for secondary columns as they link to themselves
+	header := m.columnNodes[columnIndex]
+	header.left.right = header.right
+	header.right.left = header.left
+
+	// unlink all nodes of the column from their rows
+	node := header.bottom
+	for node != header {
+		if m.rowNodes[node.rowIndex] == node {
+			if node.right == node {
+				m.rowNodes[node.rowIndex] = nil
+			} else {
+				m.rowNodes[node.rowIndex] = node.right
+			}
+		}
+		node.left.right = node.right
+		node.right.left = node.left
+		node = node.bottom
 	}
 
-	if m.columnCovered[columnIndex] {
-		return fmt.Errorf("column at %d is already covered", columnIndex)
+	m.columnNodes = append(m.columnNodes[:columnIndex], m.columnNodes[columnIndex+1:]...)
+	m.numNodesPerColumn = append(m.numNodesPerColumn[:columnIndex], m.numNodesPerColumn[columnIndex+1:]...)
+
+	// all columns after the removed one shift left by one
+	for i := columnIndex; i < len(m.columnNodes); i++ {
+		header := m.columnNodes[i]
+		header.colIndex = i
+		node := header.bottom
+		for node != header {
+			node.colIndex = i
+			node = node.bottom
+		}
 	}
+}
 
+// the links are always up to date
+func (m *DancingLinksMatrix) build() {
+}
+
+func (m *DancingLinksMatrix) cover(columnIndex int) {
 	// cover the header
 	header := m.columnNodes[columnIndex]
 	header.left.right = header.right
@@ -116,26 +168,17 @@ func (m *DancingLinksMatrix) CoverColumn(columnIndex int) error {
 		for node != row {
 			node.bottom.top = node.top
 			node.top.bottom = node.bottom
-			node = node.right
 			m.numNodesPerColumn[node.colIndex]--
+			node = node.right
 		}
 
 		row = row.bottom
 	}
 
 	m.columnCovered[columnIndex] = true
-
-	return nil
 }
 
-func (m *DancingLinksMatrix) UncoverColumn(columnIndex int) error {
-	if columnIndex < 0 || columnIndex >= len(m.columnCovered) {
-		return fmt.Errorf("column at index %d does not exist", columnIndex)
-	}
-	if !m.columnCovered[columnIndex] {
-		return fmt.Errorf("column at %d has not been covered yet", columnIndex)
-	}
-
+func (m *DancingLinksMatrix) uncover(columnIndex int) {
 	header := m.columnNodes[columnIndex]
 	row := header.top
 	for row != header {
@@ -143,8 +186,8 @@ func (m *DancingLinksMatrix) UncoverColumn(columnIndex int) error {
 		for node != row {
 			node.bottom.top = node
 			node.top.bottom = node
-			node = node.left
 			m.numNodesPerColumn[node.colIndex]++
+			node = node.left
 		}
 		row = row.top
 	}
@@ -153,32 +196,12 @@ func (m *DancingLinksMatrix) UncoverColumn(columnIndex int) error {
 	header.left.right = header
 
 	m.columnCovered[columnIndex] = false
-
-	return nil
-}
-
-func (m *DancingLinksMatrix) Columns() []string {
-	return m.columnIdentifiers
-}
-
-func (m *DancingLinksMatrix) Rows() []string {
-	return m.rowIdentifiers
-}
-
-func (m *DancingLinksMatrix) NumUncoveredColumns() int {
-	count := 0
-	for _, covered := range m.columnCovered {
-		if !covered {
-			count++
-		}
-	}
-	return count
 }
 
-func (m *DancingLinksMatrix) AsDenseMatrix() [][] bool {
-	denseMatrix := make([][]bool, len(m.rowIdentifiers))
+func (m *DancingLinksMatrix) asDenseMatrix() [][] bool {
+	denseMatrix := make([][]bool, len(m.rowNodes))
 	for i := range denseMatrix {
-		denseMatrix[i] = make([]bool, len(m.columnIdentifiers))
+		denseMatrix[i] = make([]bool, len(m.columnNodes))
 	}
 
 	for _, n := range m.columnNodes {
@@ -192,94 +215,147 @@ func (m *DancingLinksMatrix) AsDenseMatrix() [][] bool {
 	return denseMatrix
 }
 
-func (m *DancingLinksMatrix) Solve() [][]string {
-	// allocate an empty slice with 100 capacity to avoid enlarging it all the time
-	searchResult := m.search(make([]int, 0, 100), math.MaxInt32)
-	if len(searchResult) == 0 {
-		return nil
-	}
-	// map the row indices back to their names
-	return m.mapRowNames(searchResult)
+func (m *DancingLinksMatrix) newEmpty() matrixBackend {
+	return newDancingLinksMatrix(m.config)
 }
 
-func (m *DancingLinksMatrix) mapRowNames(searchResult [][]int) [][]string {
-	c := make([][]string, len(searchResult))
-	for i, row := range searchResult {
-		c[i] = make([]string, len(row))
-		for ji, j := range row {
-			c[i][ji] = m.rowIdentifiers[j]
+func (m *DancingLinksMatrix) replace(other matrixBackend) {
+	*m = *other.(*DancingLinksMatrix)
+	m.backend = m
+}
+
+// WriteLinksDOT writes the four-way linked node structure in the Graphviz DOT format. Right and bottom links are
+// drawn solid, left and top links dashed. Nodes that are currently unlinked by a cover operation are grey and still
+// point to their old neighbours, which is what allows UncoverColumn to restore them.
+func (m *DancingLinksMatrix) WriteLinksDOT(writer io.Writer) error {
+	ids := map[*Node]string{m.head: "head"}
+	labels := map[*Node]string{m.head: "head"}
+	var order []*Node
+	order = append(order, m.head)
+	for c, header := range m.columnNodes {
+		ids[header] = fmt.Sprintf("c%d", c)
+		labels[header] = m.columnIdentifiers[c]
+		order = append(order, header)
+	}
+	for r, first := range m.rowNodes {
+		if first == nil {
+			continue
+		}
+		node := first
+		for {
+			ids[node] = fmt.Sprintf("n%d_%d", r, node.colIndex)
+			labels[node] = fmt.Sprintf("%s\n%s", m.rowIdentifiers[r], m.columnIdentifiers[node.colIndex])
+			order = append(order, node)
+			node = node.right
+			if node == first {
+				break
+			}
 		}
 	}
-	return c
-}
 
-func (m *DancingLinksMatrix) SolveOne() []string {
-	searchResult := m.search(make([]int, 0, 100), 1)
-	if len(searchResult) == 0 {
-		return nil
+	sb := &strings.Builder{}
+	sb.WriteString("digraph links {\n")
+	sb.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+	for _, node := range order {
+		attributes := ""
+		if node.left.right != node || node.top.bottom != node {
+			attributes = ", color=grey, fontcolor=grey"
+		}
+		sb.WriteString(fmt.Sprintf("  %s [label=%s%s];\n", ids[node], dotQuote(labels[node]), attributes))
 	}
-	return m.mapRowNames(searchResult)[0]
-}
 
-func (m *DancingLinksMatrix) search(partialSolution []int, maxRemainingResults int) [][]int {
-	if maxRemainingResults <= 0 {
-		return nil
+	// keep the headers in one line and every row in its own line
+	sb.WriteString("  { rank=same; head;")
+	for _, header := range m.columnNodes {
+		sb.WriteString(" " + ids[header] + ";")
 	}
-	if m.head.right == m.head {
-		// we have to copy here to not interfere with other recursion steps changing the partial solution slice
-		c := make([]int, len(partialSolution), len(partialSolution))
-		copy(c, partialSolution)
-		return [][]int{c}
-	} else {
-		result := make([][]int, 0, 0)
-		nextColumn := m.chooseNext(m.head.right)
-		_ = m.CoverColumn(nextColumn.colIndex)
-		row := nextColumn.bottom
-		for row != nextColumn {
-			if len(result) >= maxRemainingResults {
+	sb.WriteString(" }\n")
+	for r, first := range m.rowNodes {
+		if first == nil {
+			continue
+		}
+		sb.WriteString("  { rank=same;")
+		for node := first; ; {
+			sb.WriteString(" " + ids[node] + ";")
+			node = node.right
+			if node == first {
 				break
 			}
+		}
+		sb.WriteString(fmt.Sprintf(" } // %s\n", m.rowIdentifiers[r]))
+	}
 
-			// we're adding the next eligible column to the solution
-			partialSolution = append(partialSolution, row.rowIndex)
-			node := row.right
-			// all other columns that are true in that row now need to be covered too
-			for node != row {
-				_ = m.CoverColumn(node.colIndex)
-				node = node.right
-			}
+	for _, node := range order {
+		from := ids[node]
+		sb.WriteString(fmt.Sprintf("  %s -> %s [label=\"R\"];\n", from, ids[node.right]))
+		sb.WriteString(fmt.Sprintf("  %s -> %s [label=\"L\", style=dashed, constraint=false];\n", from, ids[node.left]))
+		if node != m.head {
+			sb.WriteString(fmt.Sprintf("  %s -> %s [label=\"D\"];\n", from, ids[node.bottom]))
+			sb.WriteString(fmt.Sprintf("  %s -> %s [label=\"U\", style=dashed, constraint=false];\n", from, ids[node.top]))
+		}
+	}
+	sb.WriteString("}\n")
 
-			// recurse and gather any sub-solutions
-			for _, recResult := range m.search(partialSolution, maxRemainingResults-len(result)) {
-				if recResult != nil && len(recResult) > 0 {
-					result = append(result, recResult)
-					if len(result) >= maxRemainingResults {
-						break
-					}
-				}
-			}
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
 
-			// revert the last covering for the next column iteration
-			partialSolution = partialSolution[:len(partialSolution)-1]
-			node = row.left
-			// all other columns that are true in that row now need to be covered too
-			for node != row {
-				_ = m.UncoverColumn(node.colIndex)
-				node = node.left
-			}
+func (m *DancingLinksMatrix) chooseColumn() int {
+	if m.head.right == m.head {
+		return -1
+	}
+	return m.chooseNext(m.head.right).colIndex
+}
 
-			row = row.bottom
-		}
-		_ = m.UncoverColumn(nextColumn.colIndex)
+func (m *DancingLinksMatrix) appendRows(rows []int, columnIndex int) []int {
+	header := m.columnNodes[columnIndex]
+	for node := header.bottom; node != header; node = node.bottom {
+		rows = append(rows, node.rowIndex)
+	}
+	return rows
+}
 
-		if len(result) > maxRemainingResults {
-			result = result[:maxRemainingResults]
+func (m *DancingLinksMatrix) rowColumns(rowIndex int) []int {
+	var columns []int
+	first := m.rowNodes[rowIndex]
+	if first == nil {
+		return columns
+	}
+	node := first
+	for {
+		columns = append(columns, node.colIndex)
+		node = node.right
+		if node == first {
+			break
 		}
+	}
+	return columns
+}
+
+func (m *DancingLinksMatrix) selectRow(rowIndex, columnIndex int) {
+	row := m.findNode(rowIndex, columnIndex)
+	// all other columns that are true in that row now need to be covered too
+	for node := row.right; node != row; node = node.right {
+		m.cover(node.colIndex)
+	}
+}
 
-		return result
+func (m *DancingLinksMatrix) deselectRow(rowIndex, columnIndex int) {
+	row := m.findNode(rowIndex, columnIndex)
+	for node := row.left; node != row; node = node.left {
+		m.uncover(node.colIndex)
 	}
 }
 
+// returns the node of the row in the given column, the row must be true in that column
+func (m *DancingLinksMatrix) findNode(rowIndex, columnIndex int) *Node {
+	node := m.rowNodes[rowIndex]
+	for node.colIndex != columnIndex {
+		node = node.right
+	}
+	return node
+}
+
 func (m *DancingLinksMatrix) chooseNext(node *Node) *Node {
 	lowestCount := math.MaxInt32
 	lowestNode := node
@@ -294,17 +370,19 @@ func (m *DancingLinksMatrix) chooseNext(node *Node) *Node {
 	return lowestNode
 }
 
-func NewDancingLinkMatrix() DancingLinksMatrixI {
+func newDancingLinksMatrix(config searchConfig) *DancingLinksMatrix {
 	header := &Node{}
 	header.left = header
 	header.right = header
 	header.top = header
 	header.bottom = header
 
-	return &DancingLinksMatrix{
-		columnIdentifiers: []string{},
-		rowIdentifiers:    []string{},
-		columnNodes:       []*Node{},
-		head:              header,
+	m := &DancingLinksMatrix{
+		columnNodes: []*Node{},
+		rowNodes:    []*Node{},
+		head:        header,
 	}
+	m.matrix = newMatrix(m, config)
+	return m
 }
+
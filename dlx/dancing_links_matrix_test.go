@@ -3,191 +3,276 @@ package dlx
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
 func TestOneByOneMatrixCreation(t *testing.T) {
-	mat := NewDancingLinkMatrix()
-	mat.AppendColumn("1")
-	err := mat.AppendRow("A", []bool{true})
-	assert.Nil(t, err)
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewDancingLinkMatrix(backend)
+		mat.AppendColumn("1")
+		err := mat.AppendRow("A", []bool{true})
+		assert.Nil(t, err)
 
-	assert.Equal(t, []string{"1"}, mat.Columns())
-	assert.Equal(t, [][]bool{{true}}, mat.AsDenseMatrix())
+		assert.Equal(t, []string{"1"}, mat.Columns())
+		assert.Equal(t, [][]bool{{true}}, mat.AsDenseMatrix())
+	})
 }
 
 func TestSparsenessMultiColumn(t *testing.T) {
-	mat := NewDancingLinkMatrix()
-	mat.AppendColumn("1")
-	mat.AppendColumn("2")
-	err := mat.AppendRow("A", []bool{true, false})
-	assert.Nil(t, err)
-	err = mat.AppendRow("A", []bool{false, true})
-	assert.Nil(t, err)
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewDancingLinkMatrix(backend)
+		mat.AppendColumn("1")
+		mat.AppendColumn("2")
+		err := mat.AppendRow("A", []bool{true, false})
+		assert.Nil(t, err)
+		err = mat.AppendRow("B", []bool{false, true})
+		assert.Nil(t, err)
 
-	assert.Equal(t, []string{"1", "2"}, mat.Columns())
-	assert.Equal(t, [][]bool{{true, false}, {false, true}}, mat.AsDenseMatrix())
+		assert.Equal(t, []string{"1", "2"}, mat.Columns())
+		assert.Equal(t, []string{"A", "B"}, mat.Rows())
+		assert.Equal(t, [][]bool{{true, false}, {false, true}}, mat.AsDenseMatrix())
+	})
 }
 
 func TestSecondaryConstraints(t *testing.T) {
-	mat := NewDancingLinkMatrix()
-	mat.AppendColumn("1")
-	mat.AppendSecondaryColumn("2")
-	err := mat.AppendRow("A", []bool{true, false})
-	assert.Nil(t, err)
-	err = mat.AppendRow("A", []bool{false, true})
-	assert.Nil(t, err)
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewDancingLinkMatrix(backend)
+		mat.AppendColumn("1")
+		mat.AppendSecondaryColumn("2")
+		err := mat.AppendRow("A", []bool{true, false})
+		assert.Nil(t, err)
+		err = mat.AppendRow("B", []bool{false, true})
+		assert.Nil(t, err)
 
-	assert.Equal(t, []string{"1", "2"}, mat.Columns())
-	assert.Equal(t, [][]bool{{true, false}, {false, true}}, mat.AsDenseMatrix())
+		assert.Equal(t, []string{"1", "2"}, mat.Columns())
+		assert.Equal(t, [][]bool{{true, false}, {false, true}}, mat.AsDenseMatrix())
+		// the secondary column doesn't need to be covered
+		assert.Equal(t, [][]string{{"A"}}, mat.Solve())
+	})
 }
 
 func TestWikipediaExampleDataCorrectnessAsDenseMatrix(t *testing.T) {
-	mat := NewWikipediaExampleMatrix(t)
-	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7"}, mat.Columns())
-	assert.Equal(t, []string{"A", "B", "C", "D", "E", "F"}, mat.Rows())
-	expected := [][]bool{
-		{true, false, false, true, false, false, true},
-		{true, false, false, true, false, false, false},
-		{false, false, false, true, true, false, true},
-		{false, false, true, false, true, true, false},
-		{false, true, true, false, false, true, true},
-		{false, true, false, false, false, false, true},
-	}
-	assert.Equal(t, expected, mat.AsDenseMatrix())
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7"}, mat.Columns())
+		assert.Equal(t, []string{"A", "B", "C", "D", "E", "F"}, mat.Rows())
+		expected := [][]bool{
+			{true, false, false, true, false, false, true},
+			{true, false, false, true, false, false, false},
+			{false, false, false, true, true, false, true},
+			{false, false, true, false, true, true, false},
+			{false, true, true, false, false, true, true},
+			{false, true, false, false, false, false, true},
+		}
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
 }
 
 func TestRowThatMismatchesColumns(t *testing.T) {
-	mat := NewDancingLinkMatrix()
-	mat.AppendColumn("a")
-	err := mat.AppendRow("A", []bool{true, true, true})
-	assert.EqualError(t, err, "column mismatch: have only 1 columns registered, but got 3")
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewDancingLinkMatrix(backend)
+		mat.AppendColumn("a")
+		err := mat.AppendRow("A", []bool{true, true, true})
+		assert.EqualError(t, err, "column mismatch: have only 1 columns registered, but got 3")
+	})
 }
 
 func TestWikipediaExampleCoverColumn(t *testing.T) {
-	mat := NewWikipediaExampleMatrix(t)
-	assert.Nil(t, mat.CoverColumn(3))
-	assert.Equal(t, [][]bool{
-		{false, false, false, true, false, false, false},
-		{false, false, false, true, false, false, false},
-		{false, false, false, true, false, false, false},
-		{false, false, true, false, true, true, false},
-		{false, true, true, false, false, true, true},
-		{false, true, false, false, false, false, true},
-	}, mat.AsDenseMatrix())
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		assert.Nil(t, mat.CoverColumn(3))
+		assert.Equal(t, [][]bool{
+			{false, false, false, true, false, false, false},
+			{false, false, false, true, false, false, false},
+			{false, false, false, true, false, false, false},
+			{false, false, true, false, true, true, false},
+			{false, true, true, false, false, true, true},
+			{false, true, false, false, false, false, true},
+		}, mat.AsDenseMatrix())
+	})
 }
 
 func TestWikipediaExampleCoverColumnAndUncover(t *testing.T) {
-	mat := NewWikipediaExampleMatrix(t)
-	assert.Equal(t, 7, mat.NumUncoveredColumns())
-	assert.Nil(t, mat.CoverColumn(3))
-	assert.Equal(t, [][]bool{
-		{false, false, false, true, false, false, false},
-		{false, false, false, true, false, false, false},
-		{false, false, false, true, false, false, false},
-		{false, false, true, false, true, true, false},
-		{false, true, true, false, false, true, true},
-		{false, true, false, false, false, false, true},
-	}, mat.AsDenseMatrix())
-
-	assert.Equal(t, 6, mat.NumUncoveredColumns())
-	assert.Nil(t, mat.UncoverColumn(3))
-	assert.Equal(t, [][]bool{
-		{true, false, false, true, false, false, true},
-		{true, false, false, true, false, false, false},
-		{false, false, false, true, true, false, true},
-		{false, false, true, false, true, true, false},
-		{false, true, true, false, false, true, true},
-		{false, true, false, false, false, false, true},
-	}, mat.AsDenseMatrix())
-	assert.Equal(t, 7, mat.NumUncoveredColumns())
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		assert.Equal(t, 7, mat.NumUncoveredColumns())
+		assert.Nil(t, mat.CoverColumn(3))
+		assert.Equal(t, [][]bool{
+			{false, false, false, true, false, false, false},
+			{false, false, false, true, false, false, false},
+			{false, false, false, true, false, false, false},
+			{false, false, true, false, true, true, false},
+			{false, true, true, false, false, true, true},
+			{false, true, false, false, false, false, true},
+		}, mat.AsDenseMatrix())
+		assert.Equal(t, 6, mat.NumUncoveredColumns())
+
+		// D is hidden by column 3 and stays visible in there only, E isn't visible in 3 anymore
+		assert.Nil(t, mat.CoverColumn(2))
+		assert.Equal(t, [][]bool{
+			{false, false, false, true, false, false, false},
+			{false, false, false, true, false, false, false},
+			{false, false, false, true, false, false, false},
+			{false, false, true, false, false, false, false},
+			{false, false, true, false, false, false, false},
+			{false, true, false, false, false, false, true},
+		}, mat.AsDenseMatrix())
+		assert.Nil(t, mat.UncoverColumn(2))
+
+		assert.Nil(t, mat.UncoverColumn(3))
+		assert.Equal(t, [][]bool{
+			{true, false, false, true, false, false, true},
+			{true, false, false, true, false, false, false},
+			{false, false, false, true, true, false, true},
+			{false, false, true, false, true, true, false},
+			{false, true, true, false, false, true, true},
+			{false, true, false, false, false, false, true},
+		}, mat.AsDenseMatrix())
+		assert.Equal(t, 7, mat.NumUncoveredColumns())
+	})
 }
 
 func TestUncoveringNotCoveredFails(t *testing.T) {
-	mat := NewWikipediaExampleMatrix(t)
-	err := mat.UncoverColumn(1)
-	assert.EqualError(t, err, "column at 1 has not been covered yet")
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		err := mat.UncoverColumn(1)
+		assert.EqualError(t, err, "column at 1 has not been covered yet")
+	})
 }
 
 func TestCoveringWithOutOfBoundsIndexFails(t *testing.T) {
-	mat := NewWikipediaExampleMatrix(t)
-	err := mat.UncoverColumn(15)
-	assert.EqualError(t, err, "column at index 15 does not exist")
-	err = mat.UncoverColumn(-1)
-	assert.EqualError(t, err, "column at index -1 does not exist")
-	err = mat.UncoverColumn(8)
-	assert.EqualError(t, err, "column at index 8 does not exist")
-	err = mat.UncoverColumn(7)
-	assert.EqualError(t, err, "column at index 7 does not exist")
-	// this should work
-	err = mat.CoverColumn(0)
-	assert.Nil(t, err)
-	err = mat.CoverColumn(6)
-	assert.Nil(t, err)
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		err := mat.UncoverColumn(15)
+		assert.EqualError(t, err, "column at index 15 does not exist")
+		err = mat.UncoverColumn(-1)
+		assert.EqualError(t, err, "column at index -1 does not exist")
+		err = mat.UncoverColumn(8)
+		assert.EqualError(t, err, "column at index 8 does not exist")
+		err = mat.UncoverColumn(7)
+		assert.EqualError(t, err, "column at index 7 does not exist")
+		err = mat.CoverColumn(-1)
+		assert.EqualError(t, err, "column at index -1 does not exist")
+		// this should work
+		err = mat.CoverColumn(0)
+		assert.Nil(t, err)
+		err = mat.CoverColumn(6)
+		assert.Nil(t, err)
+	})
+}
+
+func TestCoveringTwiceFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		assert.Nil(t, mat.CoverColumn(1))
+		assert.EqualError(t, mat.CoverColumn(1), "column at 1 is already covered")
+	})
 }
 
 func TestUncoveringTwiceFails(t *testing.T) {
-	mat := NewWikipediaExampleMatrix(t)
-	err := mat.CoverColumn(1)
-	assert.Nil(t, err)
-	err = mat.UncoverColumn(1)
-	assert.Nil(t, err)
-	err = mat.UncoverColumn(1)
-	assert.EqualError(t, err, "column at 1 has not been covered yet")
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		err := mat.CoverColumn(1)
+		assert.Nil(t, err)
+		err = mat.UncoverColumn(1)
+		assert.Nil(t, err)
+		err = mat.UncoverColumn(1)
+		assert.EqualError(t, err, "column at 1 has not been covered yet")
+	})
+}
+
+// covering a column only shrinks the columns that lose rows, uncovering restores their sizes
+func TestCoverColumnKeepsColumnSizes(t *testing.T) {
+	mat := NewReadMeExample().(*DancingLinksMatrix)
+	assert.Equal(t, []int{3, 2, 2}, mat.numNodesPerColumn)
+	assert.Nil(t, mat.CoverColumn(0))
+	// Jack, Amanda and Jen are hidden, only Chris is left in sour cream
+	assert.Equal(t, []int{3, 0, 1}, mat.numNodesPerColumn)
+	assert.Nil(t, mat.UncoverColumn(0))
+	assert.Equal(t, []int{3, 2, 2}, mat.numNodesPerColumn)
 }
 
 func TestSolvingWikipediaExample(t *testing.T) {
-	mat := NewWikipediaExampleMatrix(t)
-	result := mat.Solve()
-	assert.Equal(t, 1, len(result))
-	// we don't really care about the ordering of the result, but it should contain B-D-F
-	assert.ElementsMatch(t, []string{"B", "D", "F"}, result[0])
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewWikipediaExampleMatrix(t, backend)
+		result := mat.Solve()
+		assert.Equal(t, 1, len(result))
+		// we don't really care about the ordering of the result, but it should contain B-D-F
+		assert.ElementsMatch(t, []string{"B", "D", "F"}, result[0])
+	})
 }
 
 func TestSolvingKnuthPaperExample(t *testing.T) {
-	mat := NewKnuthPaperExampleMatrix(t)
-	result := mat.Solve()
-	assert.Equal(t, 1, len(result))
-	assert.ElementsMatch(t, []string{"1", "4", "5"}, result[0])
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewKnuthPaperExampleMatrix(t, backend)
+		result := mat.Solve()
+		assert.Equal(t, 1, len(result))
+		assert.ElementsMatch(t, []string{"1", "4", "5"}, result[0])
+	})
 }
 
 func TestSolvingMultiSolutionExample(t *testing.T) {
-	mat := NewDancingLinkMatrix()
-	for i := 0; i < 3; i++ {
-		mat.AppendColumn(fmt.Sprintf("%d", i))
-	}
-	assert.Nil(t, mat.AppendRow("A", []bool{true, true, true}))
-	assert.Nil(t, mat.AppendRow("B", []bool{true, false, true}))
-	assert.Nil(t, mat.AppendRow("C", []bool{false, true, false}))
-	assert.Nil(t, mat.AppendRow("D", []bool{true, true, false}))
-	assert.Nil(t, mat.AppendRow("E", []bool{false, false, true}))
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewDancingLinkMatrix(backend)
+		for i := 0; i < 3; i++ {
+			mat.AppendColumn(fmt.Sprintf("%d", i))
+		}
+		assert.Nil(t, mat.AppendRow("A", []bool{true, true, true}))
+		assert.Nil(t, mat.AppendRow("B", []bool{true, false, true}))
+		assert.Nil(t, mat.AppendRow("C", []bool{false, true, false}))
+		assert.Nil(t, mat.AppendRow("D", []bool{true, true, false}))
+		assert.Nil(t, mat.AppendRow("E", []bool{false, false, true}))
 
-	result := mat.Solve()
-	assert.Equal(t, 3, len(result))
-	assert.ElementsMatch(t, []string{"A"}, result[0])
-	assert.ElementsMatch(t, []string{"B", "C"}, result[1])
-	assert.ElementsMatch(t, []string{"D", "E"}, result[2])
+		// backends may find the solutions in a different order
+		expected := [][]string{{"A"}, {"B", "C"}, {"D", "E"}}
+		assertSameSolutions(t, expected, mat.Solve())
+		assert.Contains(t, normalizeSolutions(expected), normalizeSolutions([][]string{mat.SolveOne()})[0])
+		assert.Equal(t, 3, mat.Count())
+	})
 }
 
-func TestReadMeExample(t *testing.T) {
-	mat := NewReadMeExample()
+func TestSolvingWithoutSolution(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendColumn("chips"))
+		assert.Nil(t, mat.Solve())
+		assert.Nil(t, mat.SolveOne())
+		assert.Equal(t, 0, mat.Count())
+	})
+}
+
+func TestSolvingLeavesMatrixIntact(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewKnuthPaperExampleMatrix(t, backend)
+		expected := mat.AsDenseMatrix()
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, 1, len(mat.Solve()))
+			assert.NotNil(t, mat.SolveOne())
+			assert.Equal(t, expected, mat.AsDenseMatrix())
+			assert.Equal(t, 7, mat.NumUncoveredColumns())
+		}
+	})
+}
 
-	result := mat.Solve()
-	assert.Equal(t, 2, len(result))
-	assert.ElementsMatch(t, []string{"Amanda", "Chris"}, result[0])
-	assert.ElementsMatch(t, []string{"Jen"}, result[1])
+func TestReadMeExample(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assertSameSolutions(t, [][]string{{"Amanda", "Chris"}, {"Jen"}}, mat.Solve())
+	})
 }
 
 func TestReadMeExampleSingleResultSolution(t *testing.T) {
-	mat := NewReadMeExample()
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
 
-	result := mat.SolveOne()
-	assert.Equal(t, 2, len(result))
-	assert.ElementsMatch(t, []string{"Amanda", "Chris"}, result)
+		result := mat.SolveOne()
+		assert.Contains(t, []string{"Amanda,Chris", "Jen"}, normalizeSolutions([][]string{result})[0])
+	})
 }
 
-func NewReadMeExample() DancingLinksMatrixI {
-	mat := NewDancingLinkMatrix()
+func NewReadMeExample(options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
 	mat.AppendColumn("beer")
 	mat.AppendColumn("nachos")
 	mat.AppendColumn("sour cream")
@@ -198,8 +283,8 @@ func NewReadMeExample() DancingLinksMatrixI {
 	return mat
 }
 
-func NewWikipediaExampleMatrix(t *testing.T) DancingLinksMatrixI {
-	mat := NewDancingLinkMatrix()
+func NewWikipediaExampleMatrix(t *testing.T, options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
 	for i := 1; i < 8; i++ {
 		mat.AppendColumn(fmt.Sprintf("%d", i))
 	}
@@ -213,8 +298,8 @@ func NewWikipediaExampleMatrix(t *testing.T) DancingLinksMatrixI {
 	return mat
 }
 
-func NewKnuthPaperExampleMatrix(t *testing.T) DancingLinksMatrixI {
-	mat := NewDancingLinkMatrix()
+func NewKnuthPaperExampleMatrix(t *testing.T, options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
 	for i := 1; i < 8; i++ {
 		mat.AppendColumn(fmt.Sprintf("%d", i))
 	}
@@ -227,3 +312,358 @@ func NewKnuthPaperExampleMatrix(t *testing.T) DancingLinksMatrixI {
 	assert.Nil(t, mat.AppendRow("6", []bool{false, false, false, true, true, false, true}))
 	return mat
 }
+
+func TestAppendColumnAfterRows(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendColumn("chips"))
+		assert.Equal(t, []string{"beer", "nachos", "sour cream", "chips"}, mat.Columns())
+		assert.Equal(t, [][]bool{
+			{true, false, false, false},
+			{true, true, false, false},
+			{false, false, true, false},
+			{true, true, true, false},
+		}, mat.AsDenseMatrix())
+
+		// nobody brings chips yet, so there is no solution
+		assert.Nil(t, mat.Solve())
+
+		assert.Nil(t, mat.AppendRow("Kim", []bool{false, false, false, true}))
+		result := mat.Solve()
+		assert.Equal(t, 2, len(result))
+		assert.ElementsMatch(t, []string{"Amanda", "Chris", "Kim"}, result[0])
+		assert.ElementsMatch(t, []string{"Jen", "Kim"}, result[1])
+	})
+}
+
+func TestAppendSecondaryColumnAfterRows(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendSecondaryColumn("chips"))
+		assert.Nil(t, mat.AppendRow("Kim", []bool{false, false, true, true}))
+
+		result := mat.Solve()
+		assert.Equal(t, 3, len(result))
+		assert.ElementsMatch(t, []string{"Amanda", "Chris"}, result[0])
+		assert.ElementsMatch(t, []string{"Amanda", "Kim"}, result[1])
+		assert.ElementsMatch(t, []string{"Jen"}, result[2])
+	})
+}
+
+func TestAppendWhileCoveredFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.CoverColumn(0))
+		assert.EqualError(t, mat.AppendColumn("chips"), "cannot modify the matrix while column at 0 is covered")
+		assert.EqualError(t, mat.AppendSecondaryColumn("chips"), "cannot modify the matrix while column at 0 is covered")
+		assert.EqualError(t, mat.AppendRow("Kim", []bool{true, false, false}), "cannot modify the matrix while column at 0 is covered")
+		assert.EqualError(t, mat.RemoveRow("Jen"), "cannot modify the matrix while column at 0 is covered")
+		assert.EqualError(t, mat.RemoveColumn("beer"), "cannot modify the matrix while column at 0 is covered")
+		assert.Nil(t, mat.UncoverColumn(0))
+		assert.Nil(t, mat.AppendColumn("chips"))
+	})
+}
+
+func TestRemoveRow(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.RemoveRow("Jen"))
+		assert.Equal(t, []string{"Jack", "Amanda", "Chris"}, mat.Rows())
+		assert.Equal(t, [][]bool{
+			{true, false, false},
+			{true, true, false},
+			{false, false, true},
+		}, mat.AsDenseMatrix())
+
+		result := mat.Solve()
+		assert.Equal(t, 1, len(result))
+		assert.ElementsMatch(t, []string{"Amanda", "Chris"}, result[0])
+
+		assert.Nil(t, mat.RemoveRow("Jack"))
+		assert.Equal(t, []string{"Amanda", "Chris"}, mat.Rows())
+		assert.Equal(t, [][]bool{
+			{true, true, false},
+			{false, false, true},
+		}, mat.AsDenseMatrix())
+
+		assert.Nil(t, mat.RemoveRow("Chris"))
+		assert.Nil(t, mat.Solve())
+	})
+}
+
+func TestRemoveEmptyRow(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendRow("Sam", []bool{false, false, false}))
+		assert.Nil(t, mat.AppendRow("Kim", []bool{false, false, true}))
+		assert.Nil(t, mat.RemoveRow("Sam"))
+		assert.Equal(t, []string{"Jack", "Amanda", "Chris", "Jen", "Kim"}, mat.Rows())
+		assert.Equal(t, []bool{false, false, true}, mat.AsDenseMatrix()[4])
+	})
+}
+
+func TestRemoveRowThatDoesNotExistFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.EqualError(t, mat.RemoveRow("Kim"), "row Kim does not exist")
+	})
+}
+
+func TestRemoveColumn(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.RemoveColumn("nachos"))
+		assert.Equal(t, []string{"beer", "sour cream"}, mat.Columns())
+		assert.Equal(t, [][]bool{
+			{true, false},
+			{true, false},
+			{false, true},
+			{true, true},
+		}, mat.AsDenseMatrix())
+
+		result := mat.Solve()
+		assert.Equal(t, 3, len(result))
+		assert.ElementsMatch(t, []string{"Jack", "Chris"}, result[0])
+		assert.ElementsMatch(t, []string{"Amanda", "Chris"}, result[1])
+		assert.ElementsMatch(t, []string{"Jen"}, result[2])
+
+		// removing the first column needs to keep the remaining rows intact
+		assert.Nil(t, mat.RemoveColumn("beer"))
+		assert.Equal(t, []string{"sour cream"}, mat.Columns())
+		assert.Equal(t, [][]bool{{false}, {false}, {true}, {true}}, mat.AsDenseMatrix())
+		assert.Nil(t, mat.RemoveRow("Chris"))
+		assert.Equal(t, [][]string{{"Jen"}}, mat.Solve())
+	})
+}
+
+func TestRemoveSecondaryColumn(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewDancingLinkMatrix(backend)
+		assert.Nil(t, mat.AppendColumn("1"))
+		assert.Nil(t, mat.AppendSecondaryColumn("2"))
+		assert.Nil(t, mat.AppendRow("A", []bool{true, true}))
+		assert.Nil(t, mat.AppendRow("B", []bool{true, false}))
+		assert.Nil(t, mat.RemoveColumn("2"))
+		assert.Equal(t, [][]bool{{true}, {true}}, mat.AsDenseMatrix())
+		assert.Equal(t, [][]string{{"A"}, {"B"}}, mat.Solve())
+	})
+}
+
+func TestRemoveColumnThatDoesNotExistFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.EqualError(t, mat.RemoveColumn("chips"), "column chips does not exist")
+	})
+}
+
+func TestCount(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		assert.Equal(t, 2, NewReadMeExample(backend).Count())
+		assert.Equal(t, 1, NewWikipediaExampleMatrix(t, backend).Count())
+
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.RemoveRow("Chris"))
+		assert.Nil(t, mat.RemoveRow("Jen"))
+		assert.Equal(t, 0, mat.Count())
+	})
+}
+
+func TestPushAndPop(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expectedDense := mat.AsDenseMatrix()
+
+		assert.Nil(t, mat.Push("Chris"))
+		assert.Equal(t, 1, mat.NumAssumptions())
+		assert.Equal(t, [][]string{{"Chris", "Amanda"}}, mat.Solve())
+		assert.Equal(t, []string{"Chris", "Amanda"}, mat.SolveOne())
+		assert.Equal(t, 1, mat.Count())
+
+		assert.Nil(t, mat.Push("Amanda"))
+		assert.Equal(t, [][]string{{"Chris", "Amanda"}}, mat.Solve())
+		assert.Nil(t, mat.Pop())
+		assert.Nil(t, mat.Pop())
+		assert.Equal(t, 0, mat.NumAssumptions())
+
+		assert.Equal(t, expectedDense, mat.AsDenseMatrix())
+		assert.Equal(t, 2, mat.Count())
+	})
+}
+
+// popping has to restore every link, not only the ones visible in the dense matrix
+func TestPushAndPopRestoresLinks(t *testing.T) {
+	mat := NewReadMeExample()
+	expectedLinks := &strings.Builder{}
+	assert.Nil(t, mat.(*DancingLinksMatrix).WriteLinksDOT(expectedLinks))
+
+	assert.Nil(t, mat.Push("Chris"))
+	assert.Nil(t, mat.Push("Amanda"))
+	assert.Equal(t, [][]string{{"Chris", "Amanda"}}, mat.Solve())
+	assert.Nil(t, mat.Pop())
+	assert.Nil(t, mat.Pop())
+
+	actualLinks := &strings.Builder{}
+	assert.Nil(t, mat.(*DancingLinksMatrix).WriteLinksDOT(actualLinks))
+	assert.Equal(t, expectedLinks.String(), actualLinks.String())
+}
+
+func TestPushTriesEveryCandidate(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		counts := map[string]int{}
+		for _, row := range mat.Rows() {
+			assert.Nil(t, mat.Push(row))
+			counts[row] = mat.Count()
+			assert.Nil(t, mat.Pop())
+		}
+		assert.Equal(t, map[string]int{"Jack": 0, "Amanda": 1, "Chris": 1, "Jen": 1}, counts)
+		assert.Equal(t, 2, mat.Count())
+	})
+}
+
+func TestPushMultipleRowsIsOneFrame(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.Push("Amanda", "Chris"))
+		assert.Equal(t, 1, mat.NumAssumptions())
+		assert.Equal(t, 0, mat.NumUncoveredColumns())
+		assert.Equal(t, [][]string{{"Amanda", "Chris"}}, mat.Solve())
+		assert.Nil(t, mat.Pop())
+		assert.Equal(t, 3, mat.NumUncoveredColumns())
+	})
+}
+
+func TestPushConflictingRowsFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		assert.EqualError(t, mat.Push("Amanda", "Jen"), "cannot force row Jen, its column beer is already covered")
+		assert.Equal(t, 0, mat.NumAssumptions())
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		assert.Nil(t, mat.Push("Amanda"))
+		assert.EqualError(t, mat.Push("Jack"), "cannot force row Jack, its column beer is already covered")
+		assert.Equal(t, 1, mat.NumAssumptions())
+		assert.Nil(t, mat.Pop())
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
+}
+
+func TestPushUnknownRowFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.EqualError(t, mat.Push("Chris", "Kim"), "row Kim does not exist")
+		assert.Equal(t, 0, mat.NumAssumptions())
+		assert.Equal(t, 3, mat.NumUncoveredColumns())
+	})
+}
+
+func newInterleavingExample(t *testing.T, backend MatrixOption, withU bool) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(backend)
+	for _, c := range []string{"X", "Y", "F", "G"} {
+		assert.Nil(t, mat.AppendColumn(c))
+	}
+	assert.Nil(t, mat.AppendRow("R", []bool{true, true, false, false}))
+	assert.Nil(t, mat.AppendRow("S", []bool{false, true, true, false}))
+	assert.Nil(t, mat.AppendRow("P", []bool{false, false, true, false}))
+	assert.Nil(t, mat.AppendRow("Q", []bool{false, true, false, true}))
+	assert.Nil(t, mat.AppendRow("T", []bool{true, false, false, true}))
+	if withU {
+		assert.Nil(t, mat.AppendRow("U", []bool{false, false, false, true}))
+	}
+	return mat
+}
+
+// popping a frame underneath a newer cover would uncover in the wrong order and corrupt the matrix
+func TestPopWithNewerCoverFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newInterleavingExample(t, backend, true)
+		assert.Nil(t, mat.Push("P"))
+		assert.Nil(t, mat.CoverColumnByName("X"))
+		assert.EqualError(t, mat.Pop(), "cannot pop while column at 0 is covered after the last assumption was pushed")
+		assert.Equal(t, 1, mat.NumAssumptions())
+		assert.Equal(t, [][]string{{"P", "Q"}}, mat.Solve())
+
+		assert.Nil(t, mat.UncoverColumnByName("X"))
+		assert.Nil(t, mat.Pop())
+		assertSameSolutions(t, [][]string{{"R", "P", "U"}, {"T", "S"}}, mat.Solve())
+	})
+}
+
+func TestPopWithNewerCoverKeepsMatrixIntact(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newInterleavingExample(t, backend, false)
+		expected := mat.AsDenseMatrix()
+		assert.Nil(t, mat.Push("P"))
+		assert.Nil(t, mat.CoverColumnByName("X"))
+		assert.NotNil(t, mat.Pop())
+		assert.Nil(t, mat.UncoverColumnByName("X"))
+		assert.Nil(t, mat.Pop())
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+		assert.Equal(t, 4, mat.NumUncoveredColumns())
+	})
+}
+
+func TestCoveringColumnsOfAssumptionsFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		assert.Nil(t, mat.Push("Chris"))
+		assert.EqualError(t, mat.UncoverColumnByName("sour cream"), "column at 2 is covered by an assumption")
+		assert.EqualError(t, mat.CoverColumnByName("sour cream"), "column at 2 is covered by an assumption")
+		assert.Nil(t, mat.Pop())
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+		assert.Equal(t, 2, mat.Count())
+	})
+}
+
+// a column covered before a Push can only be uncovered after the Pop
+func TestUncoveringBeneathAssumptionFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		assert.Nil(t, mat.CoverColumnByName("sour cream"))
+		assert.Nil(t, mat.Push("Amanda"))
+		assert.EqualError(t, mat.UncoverColumnByName("sour cream"),
+			"column at 2 was covered before the last assumption was pushed")
+		assert.Nil(t, mat.Pop())
+		assert.Nil(t, mat.UncoverColumnByName("sour cream"))
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
+}
+
+func TestPopWithoutPushFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.EqualError(t, mat.Pop(), "there are no assumptions to pop")
+	})
+}
+
+func TestModifyingWithAssumptionsFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.Push("Chris"))
+		assert.EqualError(t, mat.RemoveRow("Jack"), "cannot modify the matrix while 1 assumptions are pushed")
+		assert.Nil(t, mat.Pop())
+		assert.Nil(t, mat.RemoveRow("Jack"))
+	})
+}
+
+// a row without columns covers nothing when it is pushed, its frame still refers to it by index
+func TestModifyingWithEmptyRowAssumptionFails(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendRow("E", []bool{false, false, false}))
+		assert.Nil(t, mat.Push("E"))
+		assert.EqualError(t, mat.RemoveRow("E"), "cannot modify the matrix while 1 assumptions are pushed")
+		assert.EqualError(t, mat.AppendColumn("chips"), "cannot modify the matrix while 1 assumptions are pushed")
+		assert.EqualError(t, mat.AppendRow("F", []bool{true, false, false}),
+			"cannot modify the matrix while 1 assumptions are pushed")
+		assert.EqualError(t, mat.RemoveColumn("beer"), "cannot modify the matrix while 1 assumptions are pushed")
+		assert.Equal(t, [][]string{{"E", "Amanda", "Chris"}, {"E", "Jen"}}, mat.Solve())
+
+		assert.Nil(t, mat.Pop())
+		assert.Nil(t, mat.RemoveRow("E"))
+		assert.Equal(t, 2, len(mat.Solve()))
+	})
+}
@@ -1,27 +1,91 @@
 package dlx
 
+import "io"
+
 type DancingLinksMatrixI interface {
-	// Append a new column with the given name to the matrix
-	AppendColumn(columnIdentifier string)
-	// Append a new secondary column with the given name to the matrix
-	AppendSecondaryColumn(columnIdentifier string)
-	// Append a given dense row to the matrix, error is returned when the number of columns mismatch the registered ones
+	// Append a new column with the given name to the matrix. All existing rows are false in the new column.
+	// error is returned when any column is currently covered, when assumptions are pushed or when a column with the
+	// name already exists.
+	AppendColumn(columnIdentifier string) error
+	// Append a new secondary column with the given name to the matrix. All existing rows are false in the new column.
+	// error is returned when any column is currently covered, when assumptions are pushed or when a column with the
+	// name already exists.
+	AppendSecondaryColumn(columnIdentifier string) error
+	// Append a given dense row to the matrix, error is returned when the number of columns mismatch the registered ones,
+	// when any column is currently covered, when assumptions are pushed or when a row with the identifier already
+	// exists.
 	AppendRow(rowIdentifier string, rowValues []bool) error
+	// Removes the row with the given identifier, the indices of all following rows shift up by one.
+	// error is returned when the row does not exist, when any column is currently covered or assumptions are pushed.
+	RemoveRow(rowIdentifier string) error
+	// Removes the column with the given identifier, the indices of all following columns shift left by one.
+	// error is returned when the column does not exist, when any column is currently covered or assumptions are
+	// pushed.
+	RemoveColumn(columnIdentifier string) error
 	// Returns all column identifiers
 	Columns() []string
 	// Returns all row identifiers
 	Rows() []string
+	// Returns the index of the column with the given identifier and true, or false if there is no such column
+	ColumnIndex(columnIdentifier string) (int, bool)
+	// Returns the index of the row with the given identifier and true, or false if there is no such row
+	RowIndex(rowIdentifier string) (int, bool)
+	// Returns the identifiers of all columns the given row is true in, also the covered ones.
+	// Returns nil if the row does not exist.
+	RowColumns(rowIdentifier string) []string
+	// Returns statistics about the structure of the matrix that reveal common encoding mistakes before a long
+	// search starts, like primary columns without rows or duplicate rows. Covered columns are counted as well.
+	Stats() *MatrixStats
 	// Returns the internal doubly-linked-list structure as a dense matrix of booleans
 	AsDenseMatrix() [][]bool
+	// Writes the matrix as an SVG image with row and column labels, covered rows and columns are greyed out
+	WriteSVG(writer io.Writer) error
+	// Writes the matrix as a plain PBM (P1) image with one pixel per cell, covered rows and columns are left blank
+	WritePBM(writer io.Writer) error
+	// Writes the exact cover problem as DIMACS CNF for SAT solvers, every row becomes a variable. Primary columns are
+	// encoded as exactly-one and secondary columns as at-most-one constraints, the rows forced by Push as unit clauses.
+	// The rows of the columns covered by CoverColumn are excluded by negated unit clauses.
+	// Returns the row identifier of every row variable, error is returned when writing fails or the encoding is unknown.
+	ExportCNF(writer io.Writer, encoding CNFEncoding) (map[int]string, error)
+	// Writes the exact cover problem as a binary integer program in CPLEX LP format, row i becomes the variable x(i+1).
+	// Primary columns are "= 1" and secondary columns "<= 1" constraints, the rows forced by Push are fixed to 1.
+	// The columns covered by CoverColumn are left out and their rows are fixed to 0.
+	// The objective minimizes the given costs by row identifier, costs can be nil. Use ParseSolution to read the result.
+	// error is returned when a row of the costs doesn't exist, the matrix has no rows or writing fails.
+	ExportLP(writer io.Writer, costs map[string]float64) error
+	// Writes the same integer program as ExportLP in fixed MPS format.
+	ExportMPS(writer io.Writer, costs map[string]float64) error
+
+	// Encodes the matrix as JSON: the columns with their names and whether they are primary, the rows as the sparse
+	// indices of their columns and the covered columns and pushed assumptions in the order they happened.
+	MarshalJSON() ([]byte, error)
+	// Replaces this matrix with the JSON encoded one, the options this matrix was created with are kept.
+	// error is returned when the data is invalid, in that case this matrix is unchanged.
+	UnmarshalJSON(data []byte) error
+	// Encodes the same content as MarshalJSON in a compact versioned binary format, with varint-delta encoded indices.
+	MarshalBinary() ([]byte, error)
+	// Replaces this matrix with the binary encoded one, the options this matrix was created with are kept.
+	// error is returned when the data is invalid or of an unsupported version, in that case this matrix is unchanged.
+	UnmarshalBinary(data []byte) error
 
 	// Covers the given column, meaning it will unlink the whole column and all the rows where the column is true.
-	// error is returned when the column is already covered.
+	// error is returned when the column is already covered, also when an assumption covered it.
 	CoverColumn(columnIndex int) error
 	// Uncovers the column at the given index again, this undoes the CoverColumn operation.
-	// error is returned when the given column has not been covered before.
+	// error is returned when the given column has not been covered before, when an assumption covered it or when an
+	// assumption was pushed after it was covered.
 	UncoverColumn(columnIndex int) error
+	// Covers the column with the given identifier like CoverColumn.
+	// error is returned when the column does not exist or is already covered.
+	CoverColumnByName(columnIdentifier string) error
+	// Uncovers the column with the given identifier like UncoverColumn.
+	// error is returned when the column does not exist or has not been covered before.
+	UncoverColumnByName(columnIdentifier string) error
 	// Returns the number of not yet covered columns (uncovered columns)
 	NumUncoveredColumns() int
+	// Returns the column identifiers of the independent components of the uncovered matrix, two columns are in the
+	// same component when they are connected through available rows. Components without primary columns are left out.
+	Components() [][]string
 
 	// Solves this matrix, returns the results as a list, of which each element is a set of rows that covers all the columns.
 	// the first dimension would contain the number of solutions.
@@ -33,4 +97,75 @@ type DancingLinksMatrixI interface {
 	// The resulting slice contains the identifier of the rows that participate in this solution.
 	// If no solution was found, the result is nil.
 	SolveOne() []string
+
+	// Solves this matrix within the budgets of the options and returns the solutions found so far together with
+	// the limit that stopped the search. Solve and SolveOne are the same without limits and with one solution.
+	// Limits on nodes or bytes need a single search, the matrix is only decomposed when they are zero.
+	// error is returned when a limit is negative.
+	SolveWithOptions(options SolveOptions) (*SolveResult, error)
+
+	// Checks whether this matrix has exactly one solution, the search stops at the second one. Returns the solutions
+	// found as witnesses and, if there are two, the column on which they diverge. The search is never decomposed.
+	CheckUnique() *UniquenessCheck
+
+	// Starts a search that is suspended after every solution, Next continues it up to the next one. The columns
+	// stay covered while the search is suspended, so until the iterator is exhausted or closed, all methods that
+	// change or solve the matrix, including Push and Pop, return an error, or nil and 0 where they have none.
+	// Iterate returns nil in that case too. The search is never decomposed.
+	Iterate() SolutionIteratorI
+
+	// Counts all solutions of this matrix without materializing them.
+	Count() int
+
+	// Returns the solutions from offset to offset+limit in the order of the search, the order is stable as long as
+	// the matrix and the assumptions don't change. The skipped solutions are not materialized. The returned page
+	// contains a continuation token if there are more solutions. The search is never decomposed.
+	// error is returned when the offset is negative or the limit is not positive.
+	SolveRange(offset int, limit int) (*SolutionPage, error)
+	// Returns up to limit solutions that follow the page of the given continuation token, the search resumes right
+	// after the last solution of that page. error is returned when the token is invalid, belongs to another matrix
+	// or assumptions or when the limit is not positive.
+	SolveAfter(token string, limit int) (*SolutionPage, error)
+
+	// Solves this matrix and returns only one canonical solution of every class of solutions that the given
+	// symmetries map onto each other, together with the size of the class. The symmetries only need to generate the
+	// group, all their combinations are applied. The canonical solution is the one with the smallest row indices.
+	// error is returned when a symmetry doesn't map the matrix onto itself or when assumptions are pushed.
+	SolveUpToSymmetry(symmetries []Symmetry) ([]SymmetricSolution, error)
+
+	// Finds the best partial cover when there might be no exact one: non-conflicting rows that cover the most weight
+	// of the primary columns. Every primary column weighs 1 unless it is given in the weights by its identifier.
+	// The rows forced by Push are part of the packing, the columns covered by CoverColumn are neither counted nor
+	// reported as uncovered. error is returned when a weighted column doesn't exist or its weight is negative.
+	SolveMaxPacking(weights map[string]float64) (*Packing, error)
+	// Finds a cover with the least penalty when some columns are soft, which are the columns given in the penalties by
+	// their identifiers. The other columns are hard and covered like in every solve. A soft primary column costs its
+	// penalty when no row covers it, every soft column costs its penalty for every row that covers it more than once.
+	// The rows forced by Push are part of the cover. Returns nil if the hard columns can't be covered,
+	// error is returned when a soft column doesn't exist or its penalty is negative.
+	SolveMinPenalty(penalties map[string]float64) (*PenaltyCover, error)
+
+	// Classifies every row as forced, when it is part of every solution, impossible, when it is part of none, or
+	// optional otherwise. Needs at most one SolveOne per row instead of enumerating all solutions, every solution found
+	// on the way classifies many rows at once. Returns nil if there is no solution.
+	// The pushed assumptions and covered columns are respected, also columns covered after the last Push: every row
+	// is probed in a frame of its own on top of them. error is returned when such a frame can't be popped again.
+	Backbone() (*Backbone, error)
+
+	// Forces the given rows to be part of every solution by covering all of their columns. The solve methods
+	// run under all pushed assumptions and their solutions start with the forced rows.
+	// Each Push is a single frame that is undone as a whole by Pop. error is returned when a row does not exist or
+	// when one of its columns is already covered, in that case nothing is forced.
+	Push(rowIdentifiers ...string) error
+	// Undoes the last Push and restores the exact link state from before it.
+	// error is returned when there is nothing to pop or when a column was covered by CoverColumn after the last Push,
+	// that column has to be uncovered first.
+	Pop() error
+	// Returns the number of pushed assumption frames
+	NumAssumptions() int
+
+	// Enables recording of the search tree for all following Solve and SolveOne calls, every call starts a new trace.
+	// At most maxNodes nodes (including the root) are recorded to keep the tree readable.
+	// A non-positive maxNodes disables the tracing again and returns nil.
+	TraceSearch(maxNodes int) *SearchTrace
 }
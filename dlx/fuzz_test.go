@@ -0,0 +1,47 @@
+//go:build go1.18
+// +build go1.18
+
+package dlx
+
+import (
+	"testing"
+)
+
+// decodeCase reads the number of columns and a bitmask of the secondary columns from the first two bytes, every
+// further byte is a row bitmask. The rows are capped so the reference solver stays fast.
+func decodeCase(data []byte) (matrixCase, bool) {
+	if len(data) < 2 {
+		return matrixCase{}, false
+	}
+	c := matrixCase{primary: make([]bool, 1+int(data[0])%8)}
+	for i := range c.primary {
+		c.primary[i] = data[1]&(1<<uint(i)) == 0
+	}
+	for _, mask := range data[2:] {
+		if len(c.dense) == 12 {
+			break
+		}
+		row := make([]bool, len(c.primary))
+		for i := range row {
+			row[i] = mask&(1<<uint(i)) != 0
+		}
+		c.dense = append(c.dense, row)
+	}
+	return c, true
+}
+
+func FuzzAgreesWithReference(f *testing.F) {
+	// the readme example, without and with a secondary column
+	f.Add([]byte{2, 0, 0b001, 0b110, 0b011, 0b100})
+	f.Add([]byte{2, 0b100, 0b001, 0b110, 0b011, 0b100})
+	// no rows at all, and an empty row
+	f.Add([]byte{0, 0})
+	f.Add([]byte{3, 0, 0, 0b1111, 0b0011, 0b1100})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		c, ok := decodeCase(data)
+		if !ok {
+			return
+		}
+		assertAgreesWithReference(t, c)
+	})
+}
@@ -0,0 +1,45 @@
+package dlx
+
+type SolutionIteratorI interface {
+	// Runs the search up to the next solution and returns its row identifiers and true. The search is suspended
+	// in between the calls. Returns nil and false once there are no more solutions, the matrix is restored by then.
+	Next() ([]string, bool)
+	// Ends the search early and restores the matrix, Next doesn't return any solutions afterwards.
+	Close()
+}
+
+// SolutionIterator hands out the solutions of a suspended search one at a time
+type SolutionIterator struct {
+	searcher *searcher
+	done     bool
+	// the flag of the matrix that is set while the search is suspended
+	searching *bool
+}
+
+func newSolutionIterator(backend coverBackend, config *searchConfig, a *assumptions, searching *bool) *SolutionIterator {
+	config.trace.reset()
+	s := newSearcher(backend, config.trace, config.tracer, config.orderer, nil)
+	s.start(a.newPartialSolution())
+	*searching = true
+	return &SolutionIterator{searcher: s, searching: searching}
+}
+
+func (it *SolutionIterator) Next() ([]string, bool) {
+	if it.done {
+		return nil, false
+	}
+	if !it.searcher.next() {
+		it.done = true
+		*it.searching = false
+		return nil, false
+	}
+	return mapRowNames(it.searcher.rows, [][]int{it.searcher.partialSolution})[0], true
+}
+
+func (it *SolutionIterator) Close() {
+	if !it.done {
+		it.searcher.stop()
+		it.done = true
+		*it.searching = false
+	}
+}
@@ -0,0 +1,132 @@
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIterate(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newNQueensMatrix(6, backend)
+		expected := mat.AsDenseMatrix()
+		it := mat.Iterate()
+		var all [][]string
+		for solution, ok := it.Next(); ok; solution, ok = it.Next() {
+			all = append(all, solution)
+		}
+		assertSameSolutions(t, mat.Solve(), all)
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		solution, ok := it.Next()
+		assert.False(t, ok)
+		assert.Nil(t, solution)
+		it.Close()
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
+}
+
+func TestIterateSuspendsTheSearch(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newNQueensMatrix(6, backend)
+		expected := mat.AsDenseMatrix()
+		it := mat.Iterate()
+		first, ok := it.Next()
+		assert.True(t, ok)
+		assert.Equal(t, 6, len(first))
+
+		// the columns of the first solution stay covered until the search continues
+		assert.Less(t, mat.NumUncoveredColumns(), len(mat.Columns()))
+		assert.NotNil(t, mat.AppendColumn("x"))
+
+		second, ok := it.Next()
+		assert.True(t, ok)
+		assert.NotEqual(t, first, second)
+
+		it.Close()
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+		assert.Equal(t, len(mat.Columns()), mat.NumUncoveredColumns())
+		_, ok = it.Next()
+		assert.False(t, ok)
+		it.Close()
+	})
+}
+
+func TestIterateAssumptions(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.Push("Amanda"))
+		it := mat.Iterate()
+		solution, ok := it.Next()
+		assert.True(t, ok)
+		assert.Equal(t, []string{"Amanda", "Chris"}, solution)
+		_, ok = it.Next()
+		assert.False(t, ok)
+		assert.Nil(t, mat.Pop())
+	})
+}
+
+func TestIterateRejectsChangesWhileSuspended(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		assert.Nil(t, mat.Push("Chris"))
+		it := mat.Iterate()
+		solution, ok := it.Next()
+		assert.True(t, ok)
+		assert.Equal(t, []string{"Chris", "Amanda"}, solution)
+
+		suspended := "a search is suspended, its iterator has to be exhausted or closed first"
+		assert.EqualError(t, mat.Pop(), suspended)
+		assert.EqualError(t, mat.Push("Jack"), suspended)
+		assert.EqualError(t, mat.CoverColumn(0), suspended)
+		assert.EqualError(t, mat.UncoverColumn(1), suspended)
+		assert.EqualError(t, mat.AppendColumn("chips"), suspended)
+		assert.EqualError(t, mat.RemoveRow("Jen"), suspended)
+		_, err := mat.SolveRange(0, 1)
+		assert.EqualError(t, err, suspended)
+		_, err = mat.Backbone()
+		assert.EqualError(t, err, suspended)
+		assert.Nil(t, mat.Solve())
+		assert.Nil(t, mat.SolveOne())
+		assert.Nil(t, mat.CheckUnique())
+		assert.Nil(t, mat.Iterate())
+		assert.Equal(t, 0, mat.Count())
+
+		// the rejected calls left the suspended search intact
+		_, ok = it.Next()
+		assert.False(t, ok)
+		assert.Equal(t, 1, mat.NumAssumptions())
+		assert.Nil(t, mat.Pop())
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+		assert.Equal(t, 2, mat.Count())
+
+		// closing ends the search as well
+		it = mat.Iterate()
+		_, ok = it.Next()
+		assert.True(t, ok)
+		assert.Equal(t, 0, mat.Count())
+		it.Close()
+		assert.Equal(t, 2, mat.Count())
+	})
+}
+
+func TestIterateWithoutColumns(t *testing.T) {
+	it := NewDancingLinkMatrix().Iterate()
+	solution, ok := it.Next()
+	assert.True(t, ok)
+	assert.Equal(t, []string{}, solution)
+	_, ok = it.Next()
+	assert.False(t, ok)
+}
+
+// the search reuses its levels, so the allocations don't grow with the number of nodes
+func TestSearchDoesNotAllocatePerNode(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newNQueensMatrix(9, backend)
+		assert.Equal(t, 352, mat.Count())
+		allocs := testing.AllocsPerRun(5, func() {
+			mat.Count()
+		})
+		assert.Less(t, allocs, 100.0)
+	})
+}
@@ -0,0 +1,125 @@
+package dlx
+
+import (
+	"fmt"
+)
+
+// SolveOptions are the budgets of a search, a zero value means there is no such limit
+type SolveOptions struct {
+	// the most search nodes, that is rows tried, before the search stops
+	MaxNodes int
+	// the most solutions to collect
+	MaxSolutions int
+	// the most bytes the collected solutions may take up, estimated from their row identifiers and slice headers
+	MaxBytes int
+}
+
+// SolveLimit tells which budget of the SolveOptions stopped a search
+type SolveLimit int
+
+const (
+	// the search was exhausted, all solutions were collected
+	NoLimit SolveLimit = iota
+	// the search ran out of nodes, there might be more solutions
+	NodeLimit
+	// the search collected MaxSolutions solutions, there might not be any more
+	SolutionLimit
+	// the next solution didn't fit into MaxBytes anymore and was dropped
+	ByteLimit
+)
+
+func (l SolveLimit) String() string {
+	switch l {
+	case NoLimit:
+		return "NoLimit"
+	case NodeLimit:
+		return "NodeLimit"
+	case SolutionLimit:
+		return "SolutionLimit"
+	case ByteLimit:
+		return "ByteLimit"
+	default:
+		return "Unknown"
+	}
+}
+
+// SolveResult contains the solutions collected within the budgets and which budget stopped the search, if any
+type SolveResult struct {
+	// the solutions in the order they were found, nil if there are none
+	Solutions [][]string
+	Limit     SolveLimit
+}
+
+// the estimated size of a slice and a string header
+const (
+	sliceHeaderBytes  = 24
+	stringHeaderBytes = 16
+)
+
+// the number of row identifiers that are allocated at once for the solutions of solve
+const solutionBlockSize = 1024
+
+func solveWithOptions(backend coverBackend, config *searchConfig, a *assumptions, options SolveOptions) (*SolveResult, error) {
+	if options.MaxNodes < 0 || options.MaxSolutions < 0 || options.MaxBytes < 0 {
+		return nil, fmt.Errorf("limits can't be negative, but got %d nodes, %d solutions and %d bytes",
+			options.MaxNodes, options.MaxSolutions, options.MaxBytes)
+	}
+	return solve(backend, config, a, options), nil
+}
+
+// solve collects the solutions within the given budgets. Only a search that is limited by the number of solutions
+// is decomposed, the nodes and bytes are budgets of a single search.
+func solve(backend coverBackend, config *searchConfig, a *assumptions, options SolveOptions) *SolveResult {
+	config.trace.reset()
+	rowIdentifiers := backend.Rows()
+	result := &SolveResult{}
+	// the solutions are cut from larger blocks instead of allocating every one of them on its own
+	var block []string
+	bytes := 0
+	visitor := func(solution []int) bool {
+		if options.MaxBytes > 0 {
+			size := sliceHeaderBytes + stringHeaderBytes*len(solution)
+			for _, r := range solution {
+				size += len(rowIdentifiers[r])
+			}
+			if bytes+size > options.MaxBytes {
+				result.Limit = ByteLimit
+				return false
+			}
+			bytes += size
+		}
+
+		if block == nil || cap(block)-len(block) < len(solution) {
+			size := solutionBlockSize
+			if len(solution) > size {
+				size = len(solution)
+			}
+			block = make([]string, 0, size)
+		}
+		start := len(block)
+		// map the row indices back to their names
+		for _, r := range solution {
+			block = append(block, rowIdentifiers[r])
+		}
+		result.Solutions = append(result.Solutions, block[start:len(block):len(block)])
+
+		if options.MaxSolutions > 0 && len(result.Solutions) == options.MaxSolutions {
+			result.Limit = SolutionLimit
+			return false
+		}
+		return true
+	}
+
+	if options.MaxNodes == 0 && options.MaxBytes == 0 {
+		run(backend, config, a.newPartialSolution(), options.MaxSolutions, visitor)
+		return result
+	}
+
+	s := newSearcher(backend, config.trace, config.tracer, config.orderer, visitor)
+	s.maxNodes = options.MaxNodes
+	s.search(a.newPartialSolution())
+	if s.nodeLimitHit {
+		result.Limit = NodeLimit
+	}
+	return result
+}
@@ -0,0 +1,122 @@
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSolveWithoutLimits(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newNQueensMatrix(6, backend)
+		result, err := mat.SolveWithOptions(SolveOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, &SolveResult{Solutions: mat.Solve(), Limit: NoLimit}, result)
+	})
+}
+
+func TestSolveWithMaxSolutions(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newNQueensMatrix(6, backend)
+		expected := mat.AsDenseMatrix()
+		result, err := mat.SolveWithOptions(SolveOptions{MaxSolutions: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, SolutionLimit, result.Limit)
+		assert.Equal(t, mat.Solve()[:2], result.Solutions)
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		// even if there are no more solutions, the search stopped at the limit
+		result, err = mat.SolveWithOptions(SolveOptions{MaxSolutions: 4})
+		assert.Nil(t, err)
+		assert.Equal(t, SolutionLimit, result.Limit)
+		assert.Equal(t, 4, len(result.Solutions))
+	})
+}
+
+func TestSolveWithMaxSolutionsDecomposed(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newDisjointMatrix(t, 3, backend, WithDecomposition(DecomposeUpFront))
+		result, err := mat.SolveWithOptions(SolveOptions{MaxSolutions: 5})
+		assert.Nil(t, err)
+		assert.Equal(t, SolutionLimit, result.Limit)
+		assert.Equal(t, 5, len(result.Solutions))
+		for _, solution := range result.Solutions {
+			assert.Contains(t, normalizeSolutions(mat.Solve()), normalizeSolutions([][]string{solution})[0])
+		}
+	})
+}
+
+func TestSolveWithMaxNodes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+
+		// nachos is chosen first, Amanda alone is no solution yet
+		result, err := mat.SolveWithOptions(SolveOptions{MaxNodes: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, &SolveResult{Limit: NodeLimit}, result)
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		result, err = mat.SolveWithOptions(SolveOptions{MaxNodes: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, &SolveResult{Solutions: [][]string{{"Amanda", "Chris"}}, Limit: NodeLimit}, result)
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		// the last node finishes the search
+		result, err = mat.SolveWithOptions(SolveOptions{MaxNodes: 3})
+		assert.Nil(t, err)
+		assert.Equal(t, &SolveResult{Solutions: [][]string{{"Amanda", "Chris"}, {"Jen"}}, Limit: NoLimit}, result)
+	})
+}
+
+func TestSolveWithMaxNodesIsDeterministic(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newNQueensMatrix(8, backend)
+		expected := mat.AsDenseMatrix()
+		first, err := mat.SolveWithOptions(SolveOptions{MaxNodes: 500})
+		assert.Nil(t, err)
+		assert.Equal(t, NodeLimit, first.Limit)
+		assert.Less(t, len(first.Solutions), 92)
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		second, err := mat.SolveWithOptions(SolveOptions{MaxNodes: 500})
+		assert.Nil(t, err)
+		assert.Equal(t, len(first.Solutions), len(second.Solutions))
+	})
+}
+
+func TestSolveWithMaxBytes(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		// Amanda and Chris take 24 + 2*16 + 11 bytes, Jen 24 + 16 + 3 bytes
+		result, err := mat.SolveWithOptions(SolveOptions{MaxBytes: 109})
+		assert.Nil(t, err)
+		assert.Equal(t, ByteLimit, result.Limit)
+		assert.Equal(t, [][]string{{"Amanda", "Chris"}}, result.Solutions)
+
+		result, err = mat.SolveWithOptions(SolveOptions{MaxBytes: 110})
+		assert.Nil(t, err)
+		assert.Equal(t, NoLimit, result.Limit)
+		assert.Equal(t, 2, len(result.Solutions))
+
+		result, err = mat.SolveWithOptions(SolveOptions{MaxBytes: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, &SolveResult{Limit: ByteLimit}, result)
+	})
+}
+
+func TestSolveWithOptionsAssumptions(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.Push("Jen"))
+		result, err := mat.SolveWithOptions(SolveOptions{MaxNodes: 1})
+		assert.Nil(t, err)
+		assert.Equal(t, &SolveResult{Solutions: [][]string{{"Jen"}}, Limit: NoLimit}, result)
+		assert.Nil(t, mat.Pop())
+	})
+}
+
+func TestSolveWithNegativeLimits(t *testing.T) {
+	mat := NewReadMeExample()
+	_, err := mat.SolveWithOptions(SolveOptions{MaxNodes: -1})
+	assert.Equal(t, "limits can't be negative, but got -1 nodes, 0 solutions and 0 bytes", err.Error())
+}
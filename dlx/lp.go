@@ -0,0 +1,286 @@
+package dlx
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// the rows are exported as x1...xn and the columns as c1...cm, row identifiers can't be used as they may contain
+// characters that aren't allowed in the file formats
+var lpVariablePattern = regexp.MustCompile(`^x([0-9]+)$`)
+
+// solvers report infeasibility on a status line or, like CBC and HiGHS, as a line that starts with the status itself.
+// The word alone isn't enough, statistics such as "infeasible nodes: 0" show up in feasible solutions too.
+var (
+	lpStatusPattern     = regexp.MustCompile(`(?i)^\s*(solution |model )?status\b`)
+	lpInfeasiblePattern = regexp.MustCompile(`(?i)^\s*(integer\s+)?infeasible\s*(-|$)`)
+)
+
+type integerProgram struct {
+	rows    []string
+	columns []string
+	// the variables of every column
+	columnRows [][]int
+	primary    []bool
+	costs      []float64
+	fixed      []bool
+	// the columns covered by CoverColumn aren't constraints anymore, their rows are fixed to 0 instead
+	covered  []bool
+	excluded []bool
+}
+
+func newIntegerProgram(backend coverBackend, a *assumptions, costs map[string]float64) (*integerProgram, error) {
+	p := &integerProgram{
+		rows:       backend.Rows(),
+		columns:    backend.Columns(),
+		columnRows: make([][]int, len(backend.Columns())),
+		primary:    make([]bool, len(backend.Columns())),
+		costs:      make([]float64, len(backend.Rows())),
+		fixed:      make([]bool, len(backend.Rows())),
+		covered:    make([]bool, len(backend.Columns())),
+		excluded:   make([]bool, len(backend.Rows())),
+	}
+	if len(p.rows) == 0 {
+		return nil, fmt.Errorf("cannot export a matrix without rows")
+	}
+
+	for row, cost := range costs {
+		r, ok := backend.RowIndex(row)
+		if !ok {
+			return nil, fmt.Errorf("row %s does not exist", row)
+		}
+		p.costs[r] = cost
+	}
+
+	for r := range p.rows {
+		for _, c := range backend.rowColumns(r) {
+			p.columnRows[c] = append(p.columnRows[c], r)
+		}
+	}
+	for c := range p.columns {
+		p.primary[c] = backend.isPrimary(c)
+	}
+	// the columns of the forced rows are covered as well, but fixing the rows already takes care of them
+	assumed := map[int]bool{}
+	for _, frame := range a.frames {
+		for _, r := range frame.rows {
+			p.fixed[r] = true
+		}
+		for _, c := range frame.columns {
+			assumed[c] = true
+		}
+	}
+	for c := range p.columns {
+		if !backend.isCovered(c) || assumed[c] {
+			continue
+		}
+		p.covered[c] = true
+		for _, r := range p.columnRows[c] {
+			p.excluded[r] = true
+		}
+	}
+	return p, nil
+}
+
+func lpVariable(rowIndex int) string {
+	return fmt.Sprintf("x%d", rowIndex+1)
+}
+
+func lpConstraint(columnIndex int) string {
+	return fmt.Sprintf("c%d", columnIndex+1)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// exportLP writes the set partitioning problem in CPLEX LP format
+func exportLP(backend coverBackend, a *assumptions, writer io.Writer, costs map[string]float64) error {
+	p, err := newIntegerProgram(backend, a, costs)
+	if err != nil {
+		return err
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(fmt.Sprintf("\\ exact cover with %d rows and %d columns\n", len(p.rows), len(p.columns)))
+	sb.WriteString(fmt.Sprintf("\\ x1 to x%d are the rows in matrix order\n", len(p.rows)))
+	sb.WriteString("Minimize\n obj:")
+	for r, cost := range p.costs {
+		if r > 0 {
+			sb.WriteString(" +")
+		}
+		sb.WriteString(fmt.Sprintf(" %s %s", formatFloat(cost), lpVariable(r)))
+	}
+	sb.WriteString("\nSubject To\n")
+	for c, rows := range p.columnRows {
+		if p.covered[c] {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf(" %s:", lpConstraint(c)))
+		if len(rows) == 0 {
+			// an empty primary column makes the problem infeasible, just like in the matrix
+			sb.WriteString(" 0 " + lpVariable(0))
+		}
+		for i, r := range rows {
+			if i > 0 {
+				sb.WriteString(" +")
+			}
+			sb.WriteString(" " + lpVariable(r))
+		}
+		if p.primary[c] {
+			sb.WriteString(" = 1\n")
+		} else {
+			sb.WriteString(" <= 1\n")
+		}
+	}
+
+	sb.WriteString("Bounds\n")
+	for r, fixed := range p.fixed {
+		if fixed {
+			sb.WriteString(fmt.Sprintf(" %s = 1\n", lpVariable(r)))
+		} else if p.excluded[r] {
+			sb.WriteString(fmt.Sprintf(" %s = 0\n", lpVariable(r)))
+		}
+	}
+	sb.WriteString("Binary\n")
+	for r := range p.rows {
+		sb.WriteString(" " + lpVariable(r) + "\n")
+	}
+	sb.WriteString("End\n")
+
+	_, err = io.WriteString(writer, sb.String())
+	return err
+}
+
+// exportMPS writes the set partitioning problem in fixed MPS format
+func exportMPS(backend coverBackend, a *assumptions, writer io.Writer, costs map[string]float64) error {
+	p, err := newIntegerProgram(backend, a, costs)
+	if err != nil {
+		return err
+	}
+
+	// the constraints of every row, MPS is written column by column
+	rowColumns := make([][]int, len(p.rows))
+	for c, rows := range p.columnRows {
+		if p.covered[c] {
+			continue
+		}
+		for _, r := range rows {
+			rowColumns[r] = append(rowColumns[r], c)
+		}
+	}
+
+	sb := &strings.Builder{}
+	sb.WriteString(fmt.Sprintf("* exact cover with %d rows and %d columns\n", len(p.rows), len(p.columns)))
+	sb.WriteString(fmt.Sprintf("* x1 to x%d are the rows in matrix order\n", len(p.rows)))
+	sb.WriteString("NAME          EXACTCOVER\n")
+	sb.WriteString("ROWS\n")
+	sb.WriteString(" N  obj\n")
+	for c := range p.columns {
+		if p.covered[c] {
+			continue
+		}
+		if p.primary[c] {
+			sb.WriteString(" E  " + lpConstraint(c) + "\n")
+		} else {
+			sb.WriteString(" L  " + lpConstraint(c) + "\n")
+		}
+	}
+
+	sb.WriteString("COLUMNS\n")
+	for r := range p.rows {
+		sb.WriteString(fmt.Sprintf("    %-8s  %-8s  %s\n", lpVariable(r), "obj", formatFloat(p.costs[r])))
+		for _, c := range rowColumns[r] {
+			sb.WriteString(fmt.Sprintf("    %-8s  %-8s  1\n", lpVariable(r), lpConstraint(c)))
+		}
+	}
+
+	sb.WriteString("RHS\n")
+	for c := range p.columns {
+		if !p.covered[c] {
+			sb.WriteString(fmt.Sprintf("    %-8s  %-8s  1\n", "RHS", lpConstraint(c)))
+		}
+	}
+
+	sb.WriteString("BOUNDS\n")
+	for r, fixed := range p.fixed {
+		if fixed {
+			sb.WriteString(fmt.Sprintf(" FX %-8s  %-8s  1\n", "BND", lpVariable(r)))
+		} else if p.excluded[r] {
+			sb.WriteString(fmt.Sprintf(" FX %-8s  %-8s  0\n", "BND", lpVariable(r)))
+		} else {
+			sb.WriteString(fmt.Sprintf(" BV %-8s  %s\n", "BND", lpVariable(r)))
+		}
+	}
+	sb.WriteString("ENDATA\n")
+
+	_, err = io.WriteString(writer, sb.String())
+	return err
+}
+
+// ParseSolution reads the solution file of an integer programming solver for a problem written by ExportLP or
+// ExportMPS and returns the identifiers of the chosen rows in matrix order. Every line that contains a variable
+// followed by its value is understood, which covers the plain solution files of Gurobi, SCIP, HiGHS, CBC and GLPK.
+// error is returned when the solver reported an infeasible problem on a status line or a variable doesn't belong to
+// the given rows.
+func ParseSolution(reader io.Reader, rows []string) ([]string, error) {
+	chosen := make([]bool, len(rows))
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if isInfeasibleStatus(line) {
+			return nil, fmt.Errorf("the solver reported an infeasible problem: %s", strings.TrimSpace(line))
+		}
+
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields)-1; i++ {
+			match := lpVariablePattern.FindStringSubmatch(fields[i])
+			if match == nil {
+				continue
+			}
+			r, _ := strconv.Atoi(match[1])
+			if r < 1 || r > len(rows) {
+				return nil, fmt.Errorf("variable %s does not belong to any of the %d rows", fields[i], len(rows))
+			}
+			// the value is the first number after the name, GLPK puts a basis marker in between
+			value, ok := firstNumber(fields[i+1:])
+			if !ok {
+				return nil, fmt.Errorf("cannot find the value of variable %s in: %s", fields[i], strings.TrimSpace(line))
+			}
+			// solvers report binaries with some tolerance
+			chosen[r-1] = value > 0.5
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for r, c := range chosen {
+		if c {
+			result = append(result, rows[r])
+		}
+	}
+	return result, nil
+}
+
+func isInfeasibleStatus(line string) bool {
+	if lpInfeasiblePattern.MatchString(line) {
+		return true
+	}
+	return lpStatusPattern.MatchString(line) && strings.Contains(strings.ToLower(line), "infeasible")
+}
+
+func firstNumber(fields []string) (float64, bool) {
+	for _, f := range fields {
+		if value, err := strconv.ParseFloat(f, 64); err == nil {
+			return value, true
+		}
+	}
+	return 0, false
+}
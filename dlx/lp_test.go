@@ -0,0 +1,198 @@
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestExportLP(t *testing.T) {
+	mat := NewReadMeExample()
+	sb := &strings.Builder{}
+	assert.Nil(t, mat.ExportLP(sb, map[string]float64{"Jen": 2.5}))
+	assert.Equal(t, `\ exact cover with 4 rows and 3 columns
+\ x1 to x4 are the rows in matrix order
+Minimize
+ obj: 0 x1 + 0 x2 + 0 x3 + 2.5 x4
+Subject To
+ c1: x1 + x2 + x4 = 1
+ c2: x2 + x4 = 1
+ c3: x3 + x4 = 1
+Bounds
+Binary
+ x1
+ x2
+ x3
+ x4
+End
+`, sb.String())
+}
+
+func TestExportLPSecondaryAndEmptyColumns(t *testing.T) {
+	mat := NewDancingLinkMatrix()
+	assert.Nil(t, mat.AppendColumn("a"))
+	assert.Nil(t, mat.AppendSecondaryColumn("b"))
+	assert.Nil(t, mat.AppendColumn("c"))
+	assert.Nil(t, mat.AppendRow("1", []bool{true, true, false}))
+	assert.Nil(t, mat.AppendRow("2", []bool{true, false, false}))
+	assert.Nil(t, mat.Push("2"))
+	sb := &strings.Builder{}
+	assert.Nil(t, mat.ExportLP(sb, nil))
+	assert.Contains(t, sb.String(), " c2: x1 <= 1\n c3: 0 x1 = 1\nBounds\n x2 = 1\n")
+}
+
+func TestExportMPS(t *testing.T) {
+	mat := NewReadMeExample()
+	assert.Nil(t, mat.Push("Chris"))
+	sb := &strings.Builder{}
+	assert.Nil(t, mat.ExportMPS(sb, map[string]float64{"Jack": 1, "Amanda": -0.5}))
+	assert.Equal(t, `* exact cover with 4 rows and 3 columns
+* x1 to x4 are the rows in matrix order
+NAME          EXACTCOVER
+ROWS
+ N  obj
+ E  c1
+ E  c2
+ E  c3
+COLUMNS
+    x1        obj       1
+    x1        c1        1
+    x2        obj       -0.5
+    x2        c1        1
+    x2        c2        1
+    x3        obj       0
+    x3        c3        1
+    x4        obj       0
+    x4        c1        1
+    x4        c2        1
+    x4        c3        1
+RHS
+    RHS       c1        1
+    RHS       c2        1
+    RHS       c3        1
+BOUNDS
+ BV BND       x1
+ BV BND       x2
+ FX BND       x3        1
+ BV BND       x4
+ENDATA
+`, sb.String())
+}
+
+// like the CNF export, a covered column is no constraint anymore and its rows can't be chosen
+func TestExportLPCoveredColumns(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.CoverColumnByName("sour cream"))
+		assert.Nil(t, mat.Push("Amanda"))
+		sb := &strings.Builder{}
+		assert.Nil(t, mat.ExportLP(sb, nil))
+		assert.Contains(t, sb.String(), `Subject To
+ c1: x1 + x2 + x4 = 1
+ c2: x2 + x4 = 1
+Bounds
+ x2 = 1
+ x3 = 0
+ x4 = 0
+Binary
+`)
+
+		sb = &strings.Builder{}
+		assert.Nil(t, mat.ExportMPS(sb, nil))
+		assert.Contains(t, sb.String(), "ROWS\n N  obj\n E  c1\n E  c2\nCOLUMNS\n")
+		assert.Contains(t, sb.String(), "    x3        obj       0\n    x4        obj       0\n    x4        c1        1\n")
+		assert.Contains(t, sb.String(), `RHS
+    RHS       c1        1
+    RHS       c2        1
+BOUNDS
+ BV BND       x1
+ FX BND       x2        1
+ FX BND       x3        0
+ FX BND       x4        0
+ENDATA
+`)
+	})
+}
+
+func TestExportLPErrors(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.EqualError(t, mat.ExportLP(&strings.Builder{}, map[string]float64{"Kim": 1}), "row Kim does not exist")
+		assert.EqualError(t, mat.ExportMPS(&strings.Builder{}, map[string]float64{"Kim": 1}), "row Kim does not exist")
+		assert.EqualError(t, NewDancingLinkMatrix(backend).ExportLP(&strings.Builder{}, nil),
+			"cannot export a matrix without rows")
+	})
+}
+
+func TestExportLPIsTheSameForAllBackends(t *testing.T) {
+	expected := &strings.Builder{}
+	assert.Nil(t, NewWikipediaExampleMatrix(t).ExportMPS(expected, nil))
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		actual := &strings.Builder{}
+		assert.Nil(t, NewWikipediaExampleMatrix(t, backend).ExportMPS(actual, nil))
+		assert.Equal(t, expected.String(), actual.String())
+	})
+}
+
+func TestParseSolution(t *testing.T) {
+	rows := NewReadMeExample().Rows()
+	solutions := map[string]string{
+		"gurobi": `# Objective value = 0
+x1 0
+x2 1
+x3 1
+x4 -0
+`,
+		"cbc": `Optimal - objective value 0.00000000
+      1 x2                     1                       0
+      2 x3                     1                       0
+`,
+		"glpk": `Status:     INTEGER OPTIMAL
+Objective:  obj = 0 (MINimum)
+
+   No.   Row name        Activity     Lower bound   Upper bound
+------ ------------    ------------- ------------- -------------
+     1 c1                           1             1             =
+     2 c2                           1             1             =
+     3 c3                           1             1             =
+
+   No. Column name       Activity     Lower bound   Upper bound
+------ ------------    ------------- ------------- -------------
+     1 x1           *              0             0             1
+     2 x2           *              1             0             1
+     3 x3           *              1             0             1
+     4 x4           *              0             0             1
+`,
+		"scip": `solution status: optimal solution found
+objective value:                                    0
+x2                                                  1 	(obj:0)
+x3                                         0.99999999 	(obj:0)
+`,
+		// the word only counts on a status line
+		"statistics": `solution status: optimal solution found
+infeasible nodes: 0
+x2 1
+x3 1
+`,
+	}
+	for solver, solution := range solutions {
+		result, err := ParseSolution(strings.NewReader(solution), rows)
+		assert.Nil(t, err, solver)
+		assert.Equal(t, []string{"Amanda", "Chris"}, result, solver)
+	}
+}
+
+func TestParseSolutionErrors(t *testing.T) {
+	rows := NewReadMeExample().Rows()
+	_, err := ParseSolution(strings.NewReader("Infeasible - objective value 0.00000000\n"), rows)
+	assert.EqualError(t, err, "the solver reported an infeasible problem: Infeasible - objective value 0.00000000")
+	for _, status := range []string{"Integer infeasible - objective value 0", "Status:     INTEGER INFEASIBLE",
+		"solution status: infeasible", "Model status\nInfeasible"} {
+		_, err = ParseSolution(strings.NewReader(status+"\n"), rows)
+		assert.NotNil(t, err, status)
+	}
+	_, err = ParseSolution(strings.NewReader("x5 1\n"), rows)
+	assert.EqualError(t, err, "variable x5 does not belong to any of the 4 rows")
+	_, err = ParseSolution(strings.NewReader("x1 one\n"), rows)
+	assert.EqualError(t, err, "cannot find the value of variable x1 in: x1 one")
+}
@@ -0,0 +1,470 @@
+package dlx
+
+import (
+	"fmt"
+	"io"
+)
+
+// matrixBackend is a representation of the matrix. It embeds matrix, which keeps the identifiers, the kinds and the
+// covered state of the columns and implements the public surface once for all representations on top of these
+// primitives.
+type matrixBackend interface {
+	coverBackend
+
+	// appends a column to the representation, all existing rows are false in it
+	appendColumn(primary bool)
+	// appends a row that is true in the given sorted columns
+	appendRow(columns []int)
+	// removes the row or the column at the given index, the indices of all following ones shift by one
+	removeRow(rowIndex int)
+	removeColumn(columnIndex int)
+	// lays out the structures of the search after the matrix has changed, a no-op if it is up to date
+	build()
+	// returns the cells that are currently linked: a covered row only remains visible in the column that hid it
+	asDenseMatrix() [][]bool
+	// returns a new empty matrix of the same representation with the same configuration
+	newEmpty() matrixBackend
+	// replaces the content of this matrix with the given one of the same representation
+	replace(other matrixBackend)
+	// returns the embedded matrix
+	base() *matrix
+}
+
+// matrix is the state and the public surface that all representations share
+type matrix struct {
+	// the representation that embeds this matrix
+	backend           matrixBackend
+	columnIdentifiers []string
+	rowIdentifiers    []string
+	identifiers       identifierIndex
+	primary           []bool
+	columnCovered     []bool
+	config            searchConfig
+	assumptions       assumptions
+	// true while an iterator suspended its search, the search relies on the columns it covered
+	searching bool
+}
+
+func newMatrix(backend matrixBackend, config searchConfig) matrix {
+	return matrix{
+		backend:           backend,
+		columnIdentifiers: []string{},
+		rowIdentifiers:    []string{},
+		config:            config,
+	}
+}
+
+func (m *matrix) base() *matrix {
+	return m
+}
+
+func (m *matrix) AppendColumn(columnIdentifier string) error {
+	return m.appendColumn(columnIdentifier, true)
+}
+
+func (m *matrix) AppendSecondaryColumn(columnIdentifier string) error {
+	return m.appendColumn(columnIdentifier, false)
+}
+
+func (m *matrix) appendColumn(columnIdentifier string, primary bool) error {
+	if err := m.checkModifiable(); err != nil {
+		return err
+	}
+	if err := m.identifiers.addColumn(columnIdentifier, len(m.columnIdentifiers)); err != nil {
+		return err
+	}
+
+	m.backend.appendColumn(primary)
+	m.columnIdentifiers = append(m.columnIdentifiers, columnIdentifier)
+	m.primary = append(m.primary, primary)
+	m.columnCovered = append(m.columnCovered, false)
+	return nil
+}
+
+func (m *matrix) AppendRow(rowIdentifier string, rowValues []bool) error {
+	if len(rowValues) != len(m.columnIdentifiers) {
+		return fmt.Errorf("column mismatch: have only %d columns registered, but got %d",
+			len(m.columnIdentifiers), len(rowValues))
+	}
+
+	if err := m.checkModifiable(); err != nil {
+		return err
+	}
+	if err := m.identifiers.addRow(rowIdentifier, len(m.rowIdentifiers)); err != nil {
+		return err
+	}
+
+	// since this models a sparse matrix, we're only interested in true values
+	var columns []int
+	for i, v := range rowValues {
+		if v {
+			columns = append(columns, i)
+		}
+	}
+	m.backend.appendRow(columns)
+	m.rowIdentifiers = append(m.rowIdentifiers, rowIdentifier)
+	return nil
+}
+
+func (m *matrix) RemoveRow(rowIdentifier string) error {
+	if err := m.checkModifiable(); err != nil {
+		return err
+	}
+
+	rowIndex, ok := m.identifiers.row(rowIdentifier)
+	if !ok {
+		return fmt.Errorf("row %s does not exist", rowIdentifier)
+	}
+
+	m.backend.removeRow(rowIndex)
+	m.identifiers.removeRow(m.rowIdentifiers, rowIndex)
+	m.rowIdentifiers = append(m.rowIdentifiers[:rowIndex], m.rowIdentifiers[rowIndex+1:]...)
+	return nil
+}
+
+func (m *matrix) RemoveColumn(columnIdentifier string) error {
+	if err := m.checkModifiable(); err != nil {
+		return err
+	}
+
+	columnIndex, ok := m.identifiers.column(columnIdentifier)
+	if !ok {
+		return fmt.Errorf("column %s does not exist", columnIdentifier)
+	}
+
+	m.backend.removeColumn(columnIndex)
+	m.identifiers.removeColumn(m.columnIdentifiers, columnIndex)
+	m.columnIdentifiers = append(m.columnIdentifiers[:columnIndex], m.columnIdentifiers[columnIndex+1:]...)
+	m.primary = append(m.primary[:columnIndex], m.primary[columnIndex+1:]...)
+	m.columnCovered = append(m.columnCovered[:columnIndex], m.columnCovered[columnIndex+1:]...)
+	return nil
+}
+
+// the structure can only be changed safely when nothing is covered, otherwise uncovering would restore links to
+// cells that are no longer part of the matrix
+func (m *matrix) checkModifiable() error {
+	if err := m.checkIdle(); err != nil {
+		return err
+	}
+	if m.assumptions.size() > 0 {
+		return fmt.Errorf("cannot modify the matrix while %d assumptions are pushed", m.assumptions.size())
+	}
+	for i, covered := range m.columnCovered {
+		if covered {
+			return fmt.Errorf("cannot modify the matrix while column at %d is covered", i)
+		}
+	}
+	return nil
+}
+
+// the covered state belongs to a suspended search until its iterator is exhausted or closed
+func (m *matrix) checkIdle() error {
+	if m.searching {
+		return fmt.Errorf("a search is suspended, its iterator has to be exhausted or closed first")
+	}
+	return nil
+}
+
+func (m *matrix) CoverColumn(columnIndex int) error {
+	if err := m.checkIdle(); err != nil {
+		return err
+	}
+	if columnIndex < 0 || columnIndex >= len(m.columnCovered) {
+		return fmt.Errorf("column at index %d does not exist", columnIndex)
+	}
+
+	m.backend.build()
+	return m.assumptions.coverColumn(m.backend, columnIndex)
+}
+
+func (m *matrix) UncoverColumn(columnIndex int) error {
+	if err := m.checkIdle(); err != nil {
+		return err
+	}
+	if columnIndex < 0 || columnIndex >= len(m.columnCovered) {
+		return fmt.Errorf("column at index %d does not exist", columnIndex)
+	}
+
+	return m.assumptions.uncoverColumn(m.backend, columnIndex)
+}
+
+func (m *matrix) CoverColumnByName(columnIdentifier string) error {
+	columnIndex, ok := m.identifiers.column(columnIdentifier)
+	if !ok {
+		return fmt.Errorf("column %s does not exist", columnIdentifier)
+	}
+	return m.CoverColumn(columnIndex)
+}
+
+func (m *matrix) UncoverColumnByName(columnIdentifier string) error {
+	columnIndex, ok := m.identifiers.column(columnIdentifier)
+	if !ok {
+		return fmt.Errorf("column %s does not exist", columnIdentifier)
+	}
+	return m.UncoverColumn(columnIndex)
+}
+
+func (m *matrix) Columns() []string {
+	return m.columnIdentifiers
+}
+
+func (m *matrix) Rows() []string {
+	return m.rowIdentifiers
+}
+
+func (m *matrix) ColumnIndex(columnIdentifier string) (int, bool) {
+	return m.identifiers.column(columnIdentifier)
+}
+
+func (m *matrix) RowIndex(rowIdentifier string) (int, bool) {
+	return m.identifiers.row(rowIdentifier)
+}
+
+func (m *matrix) RowColumns(rowIdentifier string) []string {
+	return rowColumnNames(m.backend, rowIdentifier)
+}
+
+func (m *matrix) isPrimary(columnIndex int) bool {
+	return m.primary[columnIndex]
+}
+
+func (m *matrix) isCovered(columnIndex int) bool {
+	return m.columnCovered[columnIndex]
+}
+
+func (m *matrix) NumUncoveredColumns() int {
+	count := 0
+	for _, covered := range m.columnCovered {
+		if !covered {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *matrix) Components() [][]string {
+	m.backend.build()
+	return componentIdentifiers(m.backend)
+}
+
+func (m *matrix) Stats() *MatrixStats {
+	return stats(m.backend)
+}
+
+func (m *matrix) AsDenseMatrix() [][]bool {
+	return m.backend.asDenseMatrix()
+}
+
+func (m *matrix) WriteSVG(writer io.Writer) error {
+	return writeSVG(m.snapshot(), writer)
+}
+
+func (m *matrix) WritePBM(writer io.Writer) error {
+	return writePBM(m.snapshot(), writer)
+}
+
+// a row is covered as soon as one of its columns is, the column that was covered first hid it
+func (m *matrix) snapshot() matrixSnapshot {
+	s := matrixSnapshot{
+		columns:        m.columnIdentifiers,
+		rows:           m.rowIdentifiers,
+		cells:          make([][]bool, len(m.rowIdentifiers)),
+		coveredColumns: m.columnCovered,
+		coveredRows:    make([]bool, len(m.rowIdentifiers)),
+	}
+	for r := range m.rowIdentifiers {
+		s.cells[r] = make([]bool, len(m.columnIdentifiers))
+		for _, c := range m.backend.rowColumns(r) {
+			s.cells[r][c] = true
+			s.coveredRows[r] = s.coveredRows[r] || m.columnCovered[c]
+		}
+	}
+	return s
+}
+
+func (m *matrix) ExportCNF(writer io.Writer, encoding CNFEncoding) (map[int]string, error) {
+	return exportCNF(m.backend, &m.assumptions, writer, encoding)
+}
+
+func (m *matrix) ExportLP(writer io.Writer, costs map[string]float64) error {
+	return exportLP(m.backend, &m.assumptions, writer, costs)
+}
+
+func (m *matrix) ExportMPS(writer io.Writer, costs map[string]float64) error {
+	return exportMPS(m.backend, &m.assumptions, writer, costs)
+}
+
+func (m *matrix) MarshalJSON() ([]byte, error) {
+	return marshalModelJSON(m.backend, &m.assumptions)
+}
+
+func (m *matrix) UnmarshalJSON(data []byte) error {
+	if err := m.checkIdle(); err != nil {
+		return err
+	}
+	restored := m.backend.newEmpty()
+	if err := unmarshalModelJSON(data, restored.base()); err != nil {
+		return err
+	}
+	m.backend.replace(restored)
+	return nil
+}
+
+func (m *matrix) MarshalBinary() ([]byte, error) {
+	return marshalModelBinary(m.backend, &m.assumptions)
+}
+
+func (m *matrix) UnmarshalBinary(data []byte) error {
+	if err := m.checkIdle(); err != nil {
+		return err
+	}
+	restored := m.backend.newEmpty()
+	if err := unmarshalModelBinary(data, restored.base()); err != nil {
+		return err
+	}
+	m.backend.replace(restored)
+	return nil
+}
+
+func (m *matrix) TraceSearch(maxNodes int) *SearchTrace {
+	m.config.trace = nil
+	if maxNodes > 0 {
+		m.config.trace = newSearchTrace(maxNodes)
+	}
+	return m.config.trace
+}
+
+func (m *matrix) Backbone() (*Backbone, error) {
+	if err := m.checkIdle(); err != nil {
+		return nil, err
+	}
+	m.backend.build()
+	return backbone(m.backend, &m.assumptions)
+}
+
+func (m *matrix) Push(rowIdentifiers ...string) error {
+	if err := m.checkIdle(); err != nil {
+		return err
+	}
+	m.backend.build()
+	return m.assumptions.push(m.backend, rowIdentifiers)
+}
+
+func (m *matrix) pushRows(rowIndices []int) error {
+	if err := m.checkIdle(); err != nil {
+		return err
+	}
+	m.backend.build()
+	return m.assumptions.pushRows(m.backend, rowIndices)
+}
+
+func (m *matrix) Pop() error {
+	if err := m.checkIdle(); err != nil {
+		return err
+	}
+	return m.assumptions.pop(m.backend)
+}
+
+func (m *matrix) NumAssumptions() int {
+	return m.assumptions.size()
+}
+
+func (m *matrix) Solve() [][]string {
+	if m.searching {
+		return nil
+	}
+	m.backend.build()
+	return solveAll(m.backend, &m.config, &m.assumptions)
+}
+
+func (m *matrix) SolveOne() []string {
+	if m.searching {
+		return nil
+	}
+	m.backend.build()
+	return solveOne(m.backend, &m.config, &m.assumptions)
+}
+
+func (m *matrix) SolveWithOptions(options SolveOptions) (*SolveResult, error) {
+	if err := m.checkIdle(); err != nil {
+		return nil, err
+	}
+	m.backend.build()
+	return solveWithOptions(m.backend, &m.config, &m.assumptions, options)
+}
+
+func (m *matrix) CheckUnique() *UniquenessCheck {
+	if m.searching {
+		return nil
+	}
+	m.backend.build()
+	return checkUnique(m.backend, &m.config, &m.assumptions)
+}
+
+func (m *matrix) Iterate() SolutionIteratorI {
+	if m.searching {
+		return nil
+	}
+	m.backend.build()
+	return newSolutionIterator(m.backend, &m.config, &m.assumptions, &m.searching)
+}
+
+func (m *matrix) Count() int {
+	if m.searching {
+		return 0
+	}
+	m.backend.build()
+	return count(m.backend, &m.config, &m.assumptions)
+}
+
+func (m *matrix) SolveRange(offset int, limit int) (*SolutionPage, error) {
+	if err := m.checkIdle(); err != nil {
+		return nil, err
+	}
+	m.backend.build()
+	return solveRange(m.backend, &m.config, &m.assumptions, offset, limit)
+}
+
+func (m *matrix) SolveAfter(token string, limit int) (*SolutionPage, error) {
+	if err := m.checkIdle(); err != nil {
+		return nil, err
+	}
+	m.backend.build()
+	return solveAfter(m.backend, &m.config, &m.assumptions, token, limit)
+}
+
+func (m *matrix) SolveUpToSymmetry(symmetries []Symmetry) ([]SymmetricSolution, error) {
+	if err := m.checkIdle(); err != nil {
+		return nil, err
+	}
+	m.backend.build()
+	return solveUpToSymmetry(m.backend, &m.config, &m.assumptions, symmetries)
+}
+
+func (m *matrix) SolveMaxPacking(weights map[string]float64) (*Packing, error) {
+	if err := m.checkIdle(); err != nil {
+		return nil, err
+	}
+	m.backend.build()
+	return solveMaxPacking(m.backend, &m.assumptions, weights)
+}
+
+func (m *matrix) SolveMinPenalty(penalties map[string]float64) (*PenaltyCover, error) {
+	if err := m.checkIdle(); err != nil {
+		return nil, err
+	}
+	m.backend.build()
+	return solveMinPenalty(m.backend, &m.assumptions, penalties)
+}
+
+func NewDancingLinkMatrix(options ...MatrixOption) DancingLinksMatrixI {
+	opts := newMatrixOptions(options)
+	config := searchConfig{decomposition: opts.decomposition, tracer: opts.tracer, orderer: opts.orderer}
+	switch opts.backend {
+	case DancingCellsBackend:
+		return newDancingCellsMatrix(config)
+	case BitsetBackend:
+		return newBitsetMatrix(config)
+	}
+	return newDancingLinksMatrix(config)
+}
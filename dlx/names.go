@@ -0,0 +1,73 @@
+package dlx
+
+import "fmt"
+
+// identifierIndex maps the unique identifiers of the columns and rows to their indices
+type identifierIndex struct {
+	columns map[string]int
+	rows    map[string]int
+}
+
+func (x *identifierIndex) addColumn(columnIdentifier string, columnIndex int) error {
+	if _, ok := x.columns[columnIdentifier]; ok {
+		return fmt.Errorf("column %s already exists", columnIdentifier)
+	}
+	if x.columns == nil {
+		x.columns = map[string]int{}
+	}
+	x.columns[columnIdentifier] = columnIndex
+	return nil
+}
+
+func (x *identifierIndex) addRow(rowIdentifier string, rowIndex int) error {
+	if _, ok := x.rows[rowIdentifier]; ok {
+		return fmt.Errorf("row %s already exists", rowIdentifier)
+	}
+	if x.rows == nil {
+		x.rows = map[string]int{}
+	}
+	x.rows[rowIdentifier] = rowIndex
+	return nil
+}
+
+// removeColumn needs to be called before the column is removed from the identifiers
+func (x *identifierIndex) removeColumn(columnIdentifiers []string, columnIndex int) {
+	removeIdentifier(x.columns, columnIdentifiers, columnIndex)
+}
+
+// removeRow needs to be called before the row is removed from the identifiers
+func (x *identifierIndex) removeRow(rowIdentifiers []string, rowIndex int) {
+	removeIdentifier(x.rows, rowIdentifiers, rowIndex)
+}
+
+// all identifiers after the removed one shift by one
+func removeIdentifier(indices map[string]int, identifiers []string, index int) {
+	delete(indices, identifiers[index])
+	for i := index + 1; i < len(identifiers); i++ {
+		indices[identifiers[i]] = i - 1
+	}
+}
+
+func (x *identifierIndex) column(columnIdentifier string) (int, bool) {
+	columnIndex, ok := x.columns[columnIdentifier]
+	return columnIndex, ok
+}
+
+func (x *identifierIndex) row(rowIdentifier string) (int, bool) {
+	rowIndex, ok := x.rows[rowIdentifier]
+	return rowIndex, ok
+}
+
+// rowColumnNames returns the identifiers of all columns the row is true in, nil if the row doesn't exist
+func rowColumnNames(backend coverBackend, rowIdentifier string) []string {
+	rowIndex, ok := backend.RowIndex(rowIdentifier)
+	if !ok {
+		return nil
+	}
+	columns := backend.Columns()
+	names := []string{}
+	for _, c := range backend.rowColumns(rowIndex) {
+		names = append(names, columns[c])
+	}
+	return names
+}
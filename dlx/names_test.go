@@ -0,0 +1,97 @@
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestColumnAndRowIndex(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		c, ok := mat.ColumnIndex("sour cream")
+		assert.True(t, ok)
+		assert.Equal(t, 2, c)
+		_, ok = mat.ColumnIndex("chips")
+		assert.False(t, ok)
+
+		r, ok := mat.RowIndex("Chris")
+		assert.True(t, ok)
+		assert.Equal(t, 2, r)
+		_, ok = mat.RowIndex("Nobody")
+		assert.False(t, ok)
+	})
+}
+
+func TestDuplicateIdentifiers(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		assert.EqualError(t, mat.AppendColumn("beer"), "column beer already exists")
+		assert.EqualError(t, mat.AppendSecondaryColumn("nachos"), "column nachos already exists")
+		assert.EqualError(t, mat.AppendRow("Jen", []bool{true, false, false}), "row Jen already exists")
+
+		// nothing changed on the failed attempts
+		assert.Equal(t, []string{"beer", "nachos", "sour cream"}, mat.Columns())
+		assert.Equal(t, []string{"Jack", "Amanda", "Chris", "Jen"}, mat.Rows())
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
+}
+
+func TestIndexAfterRemoval(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.RemoveRow("Amanda"))
+		assert.Nil(t, mat.RemoveColumn("beer"))
+
+		_, ok := mat.RowIndex("Amanda")
+		assert.False(t, ok)
+		r, ok := mat.RowIndex("Jen")
+		assert.True(t, ok)
+		assert.Equal(t, 2, r)
+		_, ok = mat.ColumnIndex("beer")
+		assert.False(t, ok)
+		c, ok := mat.ColumnIndex("sour cream")
+		assert.True(t, ok)
+		assert.Equal(t, 1, c)
+
+		// the removed identifiers can be used again
+		assert.Nil(t, mat.AppendColumn("beer"))
+		assert.Nil(t, mat.AppendRow("Amanda", []bool{false, true, true}))
+		r, ok = mat.RowIndex("Amanda")
+		assert.True(t, ok)
+		assert.Equal(t, 3, r)
+		assert.Equal(t, []string{"sour cream", "beer"}, mat.RowColumns("Amanda"))
+	})
+}
+
+func TestCoverColumnByName(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		assert.Nil(t, mat.CoverColumnByName("sour cream"))
+		assert.EqualError(t, mat.CoverColumnByName("sour cream"), "column at 2 is already covered")
+		assert.Equal(t, [][]string{{"Amanda"}}, mat.Solve())
+		assert.Nil(t, mat.UncoverColumnByName("sour cream"))
+		assert.EqualError(t, mat.UncoverColumnByName("sour cream"), "column at 2 has not been covered yet")
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		assert.EqualError(t, mat.CoverColumnByName("chips"), "column chips does not exist")
+		assert.EqualError(t, mat.UncoverColumnByName("chips"), "column chips does not exist")
+	})
+}
+
+func TestRowColumns(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendRow("Nobody", []bool{false, false, false}))
+		assert.Equal(t, []string{"beer", "nachos"}, mat.RowColumns("Amanda"))
+		assert.Equal(t, []string{"beer", "nachos", "sour cream"}, mat.RowColumns("Jen"))
+		assert.Equal(t, []string{}, mat.RowColumns("Nobody"))
+		assert.Nil(t, mat.RowColumns("Bob"))
+
+		// covering doesn't change the columns of a row
+		assert.Nil(t, mat.CoverColumnByName("beer"))
+		assert.Equal(t, []string{"beer", "nachos"}, mat.RowColumns("Amanda"))
+		assert.Nil(t, mat.UncoverColumnByName("beer"))
+	})
+}
@@ -0,0 +1,137 @@
+package dlx
+
+// Backend selects the representation of the matrix
+type Backend int
+
+const (
+	// Knuth's four-way linked nodes (DLX)
+	LinkedListBackend Backend = iota
+	// Knuth's dancing cells, the items and rows are kept as sparse sets in flat arrays
+	DancingCellsBackend
+	// Algorithm X on bitsets, fast for small and dense matrices
+	BitsetBackend
+)
+
+func (b Backend) String() string {
+	switch b {
+	case LinkedListBackend:
+		return "LinkedList"
+	case DancingCellsBackend:
+		return "DancingCells"
+	case BitsetBackend:
+		return "Bitset"
+	default:
+		return "Unknown"
+	}
+}
+
+// the bitsets pay off as long as a column fits into a few words and a popcount sees enough set bits
+const (
+	bitsetMaxColumns = 256
+	bitsetMinDensity = 0.02
+)
+
+// ChooseBackend picks the fastest backend for a matrix of the given number of columns and density,
+// which is the average fraction of columns a row is true in.
+func ChooseBackend(numColumns int, density float64) Backend {
+	if numColumns <= bitsetMaxColumns && density >= bitsetMinDensity {
+		return BitsetBackend
+	}
+	return LinkedListBackend
+}
+
+// Decomposition selects whether the search splits the matrix into independent components
+type Decomposition int
+
+const (
+	// the whole matrix is searched at once
+	NoDecomposition Decomposition = iota
+	// the components are detected once before the search starts
+	DecomposeUpFront
+	// the components are detected again on every node of the search, which pays off when choosing rows
+	// splits the remaining matrix, like in many tiling problems
+	DecomposeDuringSearch
+)
+
+type matrixOptions struct {
+	decomposition Decomposition
+	tracer        Tracer
+	orderer       RowOrderer
+	backend       Backend
+	backendSet    bool
+	shapeSet      bool
+	numColumns    int
+	density       float64
+}
+
+func newMatrixOptions(options []MatrixOption) *matrixOptions {
+	opts := &matrixOptions{backend: LinkedListBackend}
+	for _, option := range options {
+		option(opts)
+	}
+	// an explicitly chosen backend always wins over the shape
+	if opts.shapeSet && !opts.backendSet {
+		opts.backend = ChooseBackend(opts.numColumns, opts.density)
+	}
+	return opts
+}
+
+// MatrixOption configures the matrix created by NewDancingLinkMatrix
+type MatrixOption func(options *matrixOptions)
+
+// WithBackend selects the representation of the matrix, the default is the LinkedListBackend.
+// All backends find the same solutions, but not necessarily in the same order.
+func WithBackend(backend Backend) MatrixOption {
+	return func(options *matrixOptions) {
+		options.backend = backend
+		options.backendSet = true
+	}
+}
+
+// WithShape tells the matrix how many columns it will have and how dense its rows are,
+// the backend is then picked by ChooseBackend unless it was set with WithBackend.
+func WithShape(numColumns int, density float64) MatrixOption {
+	return func(options *matrixOptions) {
+		options.numColumns = numColumns
+		options.density = density
+		options.shapeSet = true
+	}
+}
+
+// WithDecomposition lets the search solve the independent components of the matrix separately. Their counts are
+// multiplied and their solutions are combined lazily, instead of exploring the product of all components.
+// The search trace is not recorded for a decomposed search.
+func WithDecomposition(decomposition Decomposition) MatrixOption {
+	return func(options *matrixOptions) {
+		options.decomposition = decomposition
+	}
+}
+
+// WithTracer reports the events of every search of the matrix to the given tracer. When the matrix is decomposed,
+// the events of the searches within the components are reported.
+func WithTracer(tracer Tracer) MatrixOption {
+	return func(options *matrixOptions) {
+		options.tracer = tracer
+	}
+}
+
+// RowOrderer ranks a row that can cover the chosen column, the search tries the rows with the higher rank first.
+// Rows of the same rank are tried in the order they were appended. The ranks are asked for again whenever a column
+// is chosen, so they shouldn't change during a search.
+type RowOrderer func(column string, row string) int
+
+// WithRowOrderer lets the search try the rows of every chosen column in the order of their ranks, so SolveOne finds
+// the preferred solutions first. It only changes the order of the solutions, never which ones are found.
+func WithRowOrderer(orderer RowOrderer) MatrixOption {
+	return func(options *matrixOptions) {
+		options.orderer = orderer
+	}
+}
+
+// WithRowPriorities is a RowOrderer that ranks every row by its priority in the map, regardless of the column.
+// Rows that aren't in the map have priority zero.
+func WithRowPriorities(priorities map[string]int) MatrixOption {
+	return WithRowOrderer(func(column string, row string) int {
+		return priorities[row]
+	})
+}
@@ -0,0 +1,154 @@
+package dlx
+
+import "fmt"
+
+// Packing is the best partial cover: non-conflicting rows that cover as much weight of the primary columns as possible
+type Packing struct {
+	// the identifiers of the chosen rows
+	Rows []string
+	// the identifiers of the primary columns that none of the rows covers
+	Uncovered []string
+	// the summed weight of the covered primary columns
+	Weight float64
+}
+
+type packer struct {
+	backend coverBackend
+	weights []float64
+	// the weight of the primary columns that are still uncovered and could be covered by a row
+	remaining float64
+
+	partialSolution []int
+	weight          float64
+	best            []int
+	bestWeight      float64
+	total           float64
+}
+
+func solveMaxPacking(backend coverBackend, a *assumptions, weights map[string]float64) (*Packing, error) {
+	columns := backend.Columns()
+	p := &packer{backend: backend, weights: make([]float64, len(columns))}
+	for c := range columns {
+		if backend.isPrimary(c) {
+			p.weights[c] = 1
+		}
+	}
+	for column, weight := range weights {
+		c, ok := backend.ColumnIndex(column)
+		if !ok {
+			return nil, fmt.Errorf("column %s does not exist", column)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("weight of column %s must not be negative", column)
+		}
+		if backend.isPrimary(c) {
+			p.weights[c] = weight
+		}
+	}
+
+	// the columns covered by CoverColumn are no part of the problem anymore, unlike the ones of the forced rows
+	assumed := map[int]bool{}
+	for _, frame := range a.frames {
+		for _, c := range frame.columns {
+			assumed[c] = true
+		}
+	}
+	excluded := make([]bool, len(columns))
+	for c := range columns {
+		if backend.isCovered(c) && !assumed[c] {
+			excluded[c] = true
+			p.weights[c] = 0
+		}
+	}
+
+	for c, weight := range p.weights {
+		p.total += weight
+		if !backend.isCovered(c) {
+			p.remaining += weight
+		}
+	}
+
+	// the forced rows are part of every packing
+	p.partialSolution = a.newPartialSolution()
+	for _, r := range p.partialSolution {
+		p.weight += p.rowWeight(r)
+	}
+	p.best = append([]int{}, p.partialSolution...)
+	p.bestWeight = p.weight
+
+	p.search()
+
+	rows := backend.Rows()
+	packing := &Packing{Weight: p.bestWeight}
+	covered := make([]bool, len(columns))
+	for _, r := range p.best {
+		packing.Rows = append(packing.Rows, rows[r])
+		for _, c := range backend.rowColumns(r) {
+			covered[c] = true
+		}
+	}
+	for c, column := range columns {
+		if backend.isPrimary(c) && !covered[c] && !excluded[c] {
+			packing.Uncovered = append(packing.Uncovered, column)
+		}
+	}
+	return packing, nil
+}
+
+func (p *packer) rowWeight(rowIndex int) float64 {
+	weight := 0.0
+	for _, c := range p.backend.rowColumns(rowIndex) {
+		weight += p.weights[c]
+	}
+	return weight
+}
+
+// search returns false once a packing covers all columns, nothing can beat that
+func (p *packer) search() bool {
+	if p.weight > p.bestWeight {
+		p.best = append(p.best[:0], p.partialSolution...)
+		p.bestWeight = p.weight
+		if p.bestWeight >= p.total {
+			return false
+		}
+	}
+
+	// even covering everything that is left can't beat the best packing
+	if p.weight+p.remaining <= p.bestWeight {
+		return true
+	}
+
+	column := p.backend.chooseColumn()
+	if column < 0 {
+		return true
+	}
+
+	p.backend.cover(column)
+	proceed := true
+	for _, row := range p.backend.appendRows(nil, column) {
+		rowWeight := p.rowWeight(row)
+		p.partialSolution = append(p.partialSolution, row)
+		p.weight += rowWeight
+		p.remaining -= rowWeight
+		p.backend.selectRow(row, column)
+
+		proceed = p.search()
+
+		p.backend.deselectRow(row, column)
+		p.remaining += rowWeight
+		p.weight -= rowWeight
+		p.partialSolution = p.partialSolution[:len(p.partialSolution)-1]
+		if !proceed {
+			break
+		}
+	}
+
+	// the column may also stay uncovered, none of its rows can be chosen anymore
+	if proceed {
+		p.remaining -= p.weights[column]
+		proceed = p.search()
+		p.remaining += p.weights[column]
+	}
+	p.backend.uncover(column)
+	return proceed
+}
@@ -0,0 +1,160 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+// every two rows conflict, so only one of them can be chosen
+func newTriangleMatrix(t *testing.T, options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
+	assert.Nil(t, mat.AppendColumn("a"))
+	assert.Nil(t, mat.AppendColumn("b"))
+	assert.Nil(t, mat.AppendColumn("c"))
+	assert.Nil(t, mat.AppendRow("X", []bool{true, true, false}))
+	assert.Nil(t, mat.AppendRow("Y", []bool{false, true, true}))
+	assert.Nil(t, mat.AppendRow("Z", []bool{true, false, true}))
+	return mat
+}
+
+func TestSolveMaxPackingExactCover(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		packing, err := mat.SolveMaxPacking(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 3.0, packing.Weight)
+		assert.Nil(t, packing.Uncovered)
+		assert.Contains(t, normalizeSolutions(mat.Solve()), normalizeSolutions([][]string{packing.Rows})[0])
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
+}
+
+func TestSolveMaxPackingWithoutExactCover(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendColumn("chips"))
+		packing, err := mat.SolveMaxPacking(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 3.0, packing.Weight)
+		assert.Equal(t, []string{"chips"}, packing.Uncovered)
+
+		mat = newTriangleMatrix(t, backend)
+		packing, err = mat.SolveMaxPacking(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, 2.0, packing.Weight)
+		assert.Equal(t, 1, len(packing.Rows))
+		assert.Equal(t, 1, len(packing.Uncovered))
+	})
+}
+
+func TestSolveMaxPackingWeighted(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newTriangleMatrix(t, backend)
+		packing, err := mat.SolveMaxPacking(map[string]float64{"b": 3, "c": 3})
+		assert.Nil(t, err)
+		assert.Equal(t, &Packing{Rows: []string{"Y"}, Uncovered: []string{"a"}, Weight: 6}, packing)
+
+		packing, err = mat.SolveMaxPacking(map[string]float64{"a": 5, "b": 0, "c": 0.5})
+		assert.Nil(t, err)
+		assert.Equal(t, &Packing{Rows: []string{"Z"}, Uncovered: []string{"b"}, Weight: 5.5}, packing)
+	})
+}
+
+func TestSolveMaxPackingAssumptions(t *testing.T) {
+	mat := newTriangleMatrix(t)
+	assert.Nil(t, mat.Push("X"))
+	packing, err := mat.SolveMaxPacking(map[string]float64{"b": 3, "c": 3})
+	assert.Nil(t, err)
+	assert.Equal(t, &Packing{Rows: []string{"X"}, Uncovered: []string{"c"}, Weight: 4}, packing)
+	assert.Nil(t, mat.Pop())
+}
+
+func TestSolveMaxPackingCoveredColumns(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.CoverColumnByName("sour cream"))
+		packing, err := mat.SolveMaxPacking(map[string]float64{"sour cream": 2})
+		assert.Nil(t, err)
+		assert.Equal(t, &Packing{Rows: []string{"Amanda"}, Weight: 2}, packing)
+		assert.Nil(t, mat.UncoverColumnByName("sour cream"))
+	})
+}
+
+func TestSolveMaxPackingErrors(t *testing.T) {
+	mat := newTriangleMatrix(t)
+	_, err := mat.SolveMaxPacking(map[string]float64{"d": 1})
+	assert.EqualError(t, err, "column d does not exist")
+	_, err = mat.SolveMaxPacking(map[string]float64{"a": -1})
+	assert.EqualError(t, err, "weight of column a must not be negative")
+}
+
+func TestSolveMaxPackingAgreesWithBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(42))
+	for i := 0; i < 50; i++ {
+		numColumns := 3 + random.Intn(6)
+		numRows := 1 + random.Intn(10)
+		dense := make([][]bool, numRows)
+		for r := range dense {
+			dense[r] = make([]bool, numColumns)
+			for c := range dense[r] {
+				dense[r][c] = random.Float64() < 0.3
+			}
+		}
+		weights := map[string]float64{}
+		for c := 0; c < numColumns; c++ {
+			weights[fmt.Sprintf("%d", c)] = float64(random.Intn(4))
+		}
+		expected := bruteForceMaxPacking(dense, weights)
+
+		forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+			mat := NewDancingLinkMatrix(backend)
+			for c := 0; c < numColumns; c++ {
+				if c%3 == 2 {
+					assert.Nil(t, mat.AppendSecondaryColumn(fmt.Sprintf("%d", c)))
+				} else {
+					assert.Nil(t, mat.AppendColumn(fmt.Sprintf("%d", c)))
+				}
+			}
+			for r, row := range dense {
+				assert.Nil(t, mat.AppendRow(fmt.Sprintf("r%d", r), row))
+			}
+			packing, err := mat.SolveMaxPacking(weights)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, packing.Weight, "matrix %v", dense)
+		})
+	}
+}
+
+// tries all subsets of rows, every third column is secondary and doesn't count
+func bruteForceMaxPacking(dense [][]bool, weights map[string]float64) float64 {
+	best := 0.0
+	for subset := 0; subset < 1<<uint(len(dense)); subset++ {
+		used := make([]bool, len(dense[0]))
+		conflict := false
+		weight := 0.0
+		for r, row := range dense {
+			if subset&(1<<uint(r)) == 0 {
+				continue
+			}
+			for c, v := range row {
+				if !v {
+					continue
+				}
+				if used[c] {
+					conflict = true
+				}
+				used[c] = true
+				if c%3 != 2 {
+					weight += weights[fmt.Sprintf("%d", c)]
+				}
+			}
+		}
+		if !conflict && weight > best {
+			best = weight
+		}
+	}
+	return best
+}
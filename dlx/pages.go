@@ -0,0 +1,142 @@
+package dlx
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// the version of the continuation tokens, tokens of other versions are rejected
+const pageTokenVersion = "v1"
+
+// SolutionPage is a range of solutions in a stable order: the columns are chosen like in every search and their rows
+// are tried in the order they were appended. Without decomposition and on the LinkedList and Bitset backends this is
+// the order of Solve as well.
+type SolutionPage struct {
+	Solutions [][]string
+	// continues the search after the last solution of this page, empty when there are no more solutions
+	NextToken string
+}
+
+// the token stores the candidate index on every level of the search that led to the last solution of the page,
+// together with a fingerprint of the matrix it belongs to
+func encodePageToken(fingerprint uint64, path []int) string {
+	sb := &strings.Builder{}
+	sb.WriteString(strconv.FormatUint(fingerprint, 16))
+	for _, i := range path {
+		sb.WriteString(".")
+		sb.WriteString(strconv.Itoa(i))
+	}
+	return pageTokenVersion + "." + base64.RawURLEncoding.EncodeToString([]byte(sb.String()))
+}
+
+func decodePageToken(token string) (uint64, []int, error) {
+	invalid := fmt.Errorf("invalid continuation token %s", token)
+	if !strings.HasPrefix(token, pageTokenVersion+".") {
+		return 0, nil, invalid
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, pageTokenVersion+"."))
+	if err != nil {
+		return 0, nil, invalid
+	}
+
+	parts := strings.Split(string(decoded), ".")
+	fingerprint, err := strconv.ParseUint(parts[0], 16, 64)
+	if err != nil {
+		return 0, nil, invalid
+	}
+	path := make([]int, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		i, err := strconv.Atoi(part)
+		if err != nil || i < 0 {
+			return 0, nil, invalid
+		}
+		path = append(path, i)
+	}
+	return fingerprint, path, nil
+}
+
+// the fingerprint changes with the structure of the matrix, the covered columns and the assumption frames, all of
+// them change the search order
+func fingerprint(backend coverBackend, a *assumptions) uint64 {
+	h := fnv.New64a()
+	for c, column := range backend.Columns() {
+		_, _ = h.Write([]byte(column + "\x00"))
+		_, _ = h.Write([]byte(strconv.FormatBool(backend.isPrimary(c)) + strconv.FormatBool(backend.isCovered(c))))
+	}
+	for r, row := range backend.Rows() {
+		_, _ = h.Write([]byte(row + "\x00"))
+		for _, c := range backend.rowColumns(r) {
+			_, _ = h.Write([]byte(strconv.Itoa(c) + ","))
+		}
+	}
+	for _, frame := range a.frames {
+		for _, r := range frame.rows {
+			_, _ = h.Write([]byte(strconv.Itoa(r) + ","))
+		}
+		_, _ = h.Write([]byte(";"))
+	}
+	return h.Sum64()
+}
+
+func solveRange(backend coverBackend, config *searchConfig, a *assumptions, offset int, limit int) (*SolutionPage, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative, but was %d", offset)
+	}
+	return solvePage(backend, config, a, nil, offset, limit)
+}
+
+func solveAfter(backend coverBackend, config *searchConfig, a *assumptions, token string, limit int) (*SolutionPage, error) {
+	tokenFingerprint, path, err := decodePageToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if tokenFingerprint != fingerprint(backend, a) {
+		return nil, fmt.Errorf("the continuation token belongs to a different matrix or assumptions")
+	}
+	return solvePage(backend, config, a, path, 0, limit)
+}
+
+// solvePage skips offset solutions after the resume path without copying them, collects limit solutions and then
+// looks for one more to know whether there is a next page
+func solvePage(backend coverBackend, config *searchConfig, a *assumptions, resume []int, offset int, limit int) (*SolutionPage, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, but was %d", limit)
+	}
+
+	config.trace.reset()
+	var searchResult [][]int
+	var lastPath []int
+	skipped := 0
+	hasNext := false
+	s := newSearcher(backend, config.trace, config.tracer, config.orderer, nil)
+	s.resume = resume
+	s.sorted = true
+	s.visitor = func(solution []int) bool {
+		if skipped < offset {
+			skipped++
+			return true
+		}
+		if len(searchResult) == limit {
+			hasNext = true
+			return false
+		}
+		c := make([]int, len(solution))
+		copy(c, solution)
+		searchResult = append(searchResult, c)
+		lastPath = append(lastPath[:0], s.path...)
+		return true
+	}
+	s.search(a.newPartialSolution())
+
+	page := &SolutionPage{}
+	if len(searchResult) > 0 {
+		page.Solutions = mapRowNames(backend.Rows(), searchResult)
+	}
+	if hasNext {
+		page.NextToken = encodePageToken(fingerprint(backend, a), lastPath)
+	}
+	return page, nil
+}
@@ -0,0 +1,142 @@
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSolveRange(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newNQueensMatrix(6, backend)
+		all, err := mat.SolveRange(0, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, normalizeSolutions(mat.Solve()), normalizeSolutions(all.Solutions))
+
+		page, err := mat.SolveRange(0, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, all.Solutions[:3], page.Solutions)
+		assert.NotEmpty(t, page.NextToken)
+
+		next, err := mat.SolveAfter(page.NextToken, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, all.Solutions[3:], next.Solutions)
+		assert.Empty(t, next.NextToken)
+
+		page, err = mat.SolveRange(1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, all.Solutions[1:3], page.Solutions)
+		assert.NotEmpty(t, page.NextToken)
+
+		// the last page doesn't point to another one
+		page, err = mat.SolveRange(2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, all.Solutions[2:], page.Solutions)
+		assert.Empty(t, page.NextToken)
+
+		page, err = mat.SolveRange(4, 1)
+		assert.Nil(t, err)
+		assert.Nil(t, page.Solutions)
+		assert.Empty(t, page.NextToken)
+	})
+}
+
+func TestSolveRangeFollowingTokens(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newNQueensMatrix(8, backend)
+		expected := mat.AsDenseMatrix()
+		page, err := mat.SolveRange(0, 10)
+		assert.Nil(t, err)
+		all := page.Solutions
+		for page.NextToken != "" {
+			page, err = mat.SolveAfter(page.NextToken, 10)
+			assert.Nil(t, err)
+			all = append(all, page.Solutions...)
+		}
+		assert.Equal(t, 92, len(all))
+		assert.Equal(t, normalizeSolutions(mat.Solve()), normalizeSolutions(all))
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		// the pages don't depend on the searches before them
+		page, err = mat.SolveRange(40, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, all[40:50], page.Solutions)
+	})
+}
+
+func TestSolveRangeWithoutColumns(t *testing.T) {
+	mat := NewDancingLinkMatrix()
+	page, err := mat.SolveRange(0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, &SolutionPage{Solutions: [][]string{{}}}, page)
+}
+
+func TestSolveRangeErrors(t *testing.T) {
+	mat := NewReadMeExample()
+	_, err := mat.SolveRange(-1, 1)
+	assert.EqualError(t, err, "offset must not be negative, but was -1")
+	_, err = mat.SolveRange(0, 0)
+	assert.EqualError(t, err, "limit must be positive, but was 0")
+	_, err = mat.SolveAfter("v1.!", 1)
+	assert.EqualError(t, err, "invalid continuation token v1.!")
+	_, err = mat.SolveAfter("v0.MQ", 1)
+	assert.EqualError(t, err, "invalid continuation token v0.MQ")
+
+	page, err := mat.SolveRange(0, 1)
+	assert.Nil(t, err)
+	_, err = mat.SolveAfter(page.NextToken, 0)
+	assert.EqualError(t, err, "limit must be positive, but was 0")
+
+	assert.Nil(t, mat.Push("Jen"))
+	_, err = mat.SolveAfter(page.NextToken, 1)
+	assert.EqualError(t, err, "the continuation token belongs to a different matrix or assumptions")
+	assert.Nil(t, mat.Pop())
+	_, err = mat.SolveAfter(page.NextToken, 1)
+	assert.Nil(t, err)
+
+	assert.Nil(t, mat.RemoveRow("Jack"))
+	_, err = mat.SolveAfter(page.NextToken, 1)
+	assert.EqualError(t, err, "the continuation token belongs to a different matrix or assumptions")
+}
+
+func TestSolveAfterChecksColumnsAndFrames(t *testing.T) {
+	mat := NewReadMeExample()
+	page, err := mat.SolveRange(0, 1)
+	assert.Nil(t, err)
+
+	assert.Nil(t, mat.CoverColumnByName("sour cream"))
+	_, err = mat.SolveAfter(page.NextToken, 1)
+	assert.EqualError(t, err, "the continuation token belongs to a different matrix or assumptions")
+	assert.Nil(t, mat.UncoverColumnByName("sour cream"))
+	_, err = mat.SolveAfter(page.NextToken, 1)
+	assert.Nil(t, err)
+
+	secondary := NewDancingLinkMatrix()
+	assert.Nil(t, secondary.AppendColumn("beer"))
+	assert.Nil(t, secondary.AppendColumn("nachos"))
+	assert.Nil(t, secondary.AppendSecondaryColumn("sour cream"))
+	for _, row := range mat.Rows() {
+		dense := make([]bool, 3)
+		for _, column := range mat.RowColumns(row) {
+			c, _ := mat.ColumnIndex(column)
+			dense[c] = true
+		}
+		assert.Nil(t, secondary.AppendRow(row, dense))
+	}
+	_, err = secondary.SolveAfter(page.NextToken, 1)
+	assert.EqualError(t, err, "the continuation token belongs to a different matrix or assumptions")
+
+	// the same forced rows in one or in two frames
+	single := newDisjointMatrix(t, 2).(*DancingLinksMatrix)
+	assert.Nil(t, single.Push("0_A", "1_A"))
+	split := newDisjointMatrix(t, 2).(*DancingLinksMatrix)
+	assert.Nil(t, split.Push("0_A"))
+	assert.Nil(t, split.Push("1_A"))
+	assert.NotEqual(t, fingerprint(single, &single.assumptions), fingerprint(split, &split.assumptions))
+}
+
+func TestSolveRangeFollowsSolveOrder(t *testing.T) {
+	mat := newNQueensMatrix(8)
+	page, err := mat.SolveRange(0, 100)
+	assert.Nil(t, err)
+	assert.Equal(t, mat.Solve(), page.Solutions)
+}
@@ -0,0 +1,282 @@
+package dlx
+
+import (
+	"fmt"
+	"math"
+)
+
+// PenaltyCover is a cheapest cover when some columns are soft: the hard columns are covered exactly like in every
+// solve, the soft ones may deviate from that at the cost of their penalty.
+type PenaltyCover struct {
+	// the identifiers of the chosen rows, the forced rows first
+	Rows []string
+	// the summed penalties of all deviations
+	Penalty float64
+	// the soft primary columns that none of the rows covers
+	Uncovered []string
+	// the soft columns that more than one of the rows covers
+	Overcovered []string
+}
+
+// penaltySearch is Algorithm X on counters: a row is available while nothing hides it, that is it wasn't chosen,
+// excluded or blocked by a chosen row on one of its hard columns. Only the uncovered columns and the available rows
+// of the matrix take part, indexed like in the backend.
+type penaltySearch struct {
+	penalties []float64
+	soft      []bool
+	primary   []bool
+	// the hard and the soft columns of every row
+	rowHard [][]int
+	rowSoft [][]int
+	// the rows of every hard column
+	columnRows [][]int
+	// the hard primary columns, which need to be covered exactly once
+	hardPrimary []int
+	// the soft columns, the primary ones cost their penalty when uncovered
+	softColumns []int
+
+	// how often every row is hidden, it's available at zero
+	hidden []int
+	// the number of available rows of every column
+	size []int
+	// whether a hard column is covered and how often a soft column is
+	covered []bool
+	count   []int
+	// the penalty for covering soft columns more than once, it only grows with every chosen row
+	overPenalty float64
+
+	chosen      []int
+	best        []int
+	bestPenalty float64
+}
+
+func solveMinPenalty(backend coverBackend, a *assumptions, penalties map[string]float64) (*PenaltyCover, error) {
+	columns := backend.Columns()
+	s := &penaltySearch{
+		penalties:   make([]float64, len(columns)),
+		soft:        make([]bool, len(columns)),
+		primary:     make([]bool, len(columns)),
+		columnRows:  make([][]int, len(columns)),
+		size:        make([]int, len(columns)),
+		covered:     make([]bool, len(columns)),
+		count:       make([]int, len(columns)),
+		bestPenalty: math.Inf(1),
+	}
+	for column, penalty := range penalties {
+		c, ok := backend.ColumnIndex(column)
+		if !ok {
+			return nil, fmt.Errorf("column %s does not exist", column)
+		}
+		if penalty < 0 {
+			return nil, fmt.Errorf("penalty of column %s must not be negative", column)
+		}
+		s.penalties[c] = penalty
+		s.soft[c] = true
+	}
+
+	// covered columns are done like in every solve, their rows aren't available anymore
+	rows := backend.Rows()
+	available := make([]bool, len(rows))
+	for c := range columns {
+		s.primary[c] = backend.isPrimary(c)
+		if backend.isCovered(c) {
+			continue
+		}
+		if s.soft[c] {
+			s.softColumns = append(s.softColumns, c)
+		} else if s.primary[c] {
+			s.hardPrimary = append(s.hardPrimary, c)
+		}
+		for _, r := range backend.appendRows(nil, c) {
+			available[r] = true
+		}
+	}
+
+	s.hidden = make([]int, len(rows))
+	s.rowHard = make([][]int, len(rows))
+	s.rowSoft = make([][]int, len(rows))
+	for r := range rows {
+		if !available[r] {
+			s.hidden[r] = 1
+			continue
+		}
+		for _, c := range backend.rowColumns(r) {
+			if s.soft[c] {
+				s.rowSoft[r] = append(s.rowSoft[r], c)
+			} else {
+				s.rowHard[r] = append(s.rowHard[r], c)
+				s.columnRows[c] = append(s.columnRows[c], r)
+			}
+			s.size[c]++
+		}
+	}
+
+	s.search(0)
+	if math.IsInf(s.bestPenalty, 1) {
+		return nil, nil
+	}
+
+	result := &PenaltyCover{Penalty: s.bestPenalty}
+	for _, r := range append(a.newPartialSolution(), s.best...) {
+		result.Rows = append(result.Rows, rows[r])
+	}
+	count := make([]int, len(columns))
+	for _, r := range s.best {
+		for _, c := range s.rowSoft[r] {
+			count[c]++
+		}
+	}
+	for _, c := range s.softColumns {
+		if count[c] == 0 && s.primary[c] {
+			result.Uncovered = append(result.Uncovered, columns[c])
+		} else if count[c] > 1 {
+			result.Overcovered = append(result.Overcovered, columns[c])
+		}
+	}
+	return result, nil
+}
+
+func (s *penaltySearch) hide(row int) {
+	if s.hidden[row] == 0 {
+		for _, c := range s.rowHard[row] {
+			s.size[c]--
+		}
+		for _, c := range s.rowSoft[row] {
+			s.size[c]--
+		}
+	}
+	s.hidden[row]++
+}
+
+func (s *penaltySearch) unhide(row int) {
+	s.hidden[row]--
+	if s.hidden[row] == 0 {
+		for _, c := range s.rowHard[row] {
+			s.size[c]++
+		}
+		for _, c := range s.rowSoft[row] {
+			s.size[c]++
+		}
+	}
+}
+
+// choose adds the row to the cover and blocks all other rows of its hard columns
+func (s *penaltySearch) choose(row int) {
+	s.hide(row)
+	for _, c := range s.rowHard[row] {
+		s.covered[c] = true
+		for _, r := range s.columnRows[c] {
+			if r != row {
+				s.hide(r)
+			}
+		}
+	}
+	for _, c := range s.rowSoft[row] {
+		if s.count[c] > 0 {
+			s.overPenalty += s.penalties[c]
+		}
+		s.count[c]++
+	}
+	s.chosen = append(s.chosen, row)
+}
+
+// unchoose undoes choose in reverse order
+func (s *penaltySearch) unchoose(row int) {
+	s.chosen = s.chosen[:len(s.chosen)-1]
+	for i := len(s.rowSoft[row]) - 1; i >= 0; i-- {
+		c := s.rowSoft[row][i]
+		s.count[c]--
+		if s.count[c] > 0 {
+			s.overPenalty -= s.penalties[c]
+		}
+	}
+	for i := len(s.rowHard[row]) - 1; i >= 0; i-- {
+		c := s.rowHard[row][i]
+		for _, r := range s.columnRows[c] {
+			if r != row {
+				s.unhide(r)
+			}
+		}
+		s.covered[c] = false
+	}
+	s.unhide(row)
+}
+
+// lowerBound is the penalty so far together with the soft primary columns that no available row can cover anymore
+func (s *penaltySearch) lowerBound() float64 {
+	bound := s.overPenalty
+	for _, c := range s.softColumns {
+		if s.primary[c] && s.count[c] == 0 && s.size[c] == 0 {
+			bound += s.penalties[c]
+		}
+	}
+	return bound
+}
+
+func (s *penaltySearch) lowersPenalty(row int) bool {
+	for _, c := range s.rowSoft[row] {
+		if s.primary[c] && s.count[c] == 0 && s.penalties[c] > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// search first covers the hard primary columns exactly, then decides about the remaining rows with soft columns
+// from the given index on. Returns false once a cover without any penalty is found, nothing can beat that.
+func (s *penaltySearch) search(next int) bool {
+	if s.lowerBound() >= s.bestPenalty {
+		return true
+	}
+
+	column := -1
+	for _, c := range s.hardPrimary {
+		if !s.covered[c] && (column < 0 || s.size[c] < s.size[column]) {
+			column = c
+		}
+	}
+	if column >= 0 {
+		// every row that covers the column hides the others, so the candidates need to be collected first
+		var candidates []int
+		for _, r := range s.columnRows[column] {
+			if s.hidden[r] == 0 {
+				candidates = append(candidates, r)
+			}
+		}
+		for _, r := range candidates {
+			s.choose(r)
+			proceed := s.search(0)
+			s.unchoose(r)
+			if !proceed {
+				return false
+			}
+		}
+		return true
+	}
+
+	// the hard primary columns are covered, all rows that are still available only have soft or hard secondary
+	// columns. Only rows that cover a soft primary column for the first time can lower the penalty, as the counts
+	// only grow this stays true for the rows that are passed over.
+	for next < len(s.hidden) && (s.hidden[next] > 0 || !s.lowersPenalty(next)) {
+		next++
+	}
+	if next == len(s.hidden) {
+		// every soft primary column without a row counts now, the lower bound is the penalty of this cover
+		s.best = append(s.best[:0], s.chosen...)
+		s.bestPenalty = s.lowerBound()
+		return s.bestPenalty > 0
+	}
+
+	s.choose(next)
+	proceed := s.search(next + 1)
+	s.unchoose(next)
+	if !proceed {
+		return false
+	}
+
+	// leaving the row out for good
+	s.hide(next)
+	proceed = s.search(next + 1)
+	s.unhide(next)
+	return proceed
+}
@@ -0,0 +1,168 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// newSoftMatrix has the hard column a and the soft columns s and t
+func newSoftMatrix(t *testing.T, options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
+	assert.Nil(t, mat.AppendColumn("a"))
+	assert.Nil(t, mat.AppendColumn("s"))
+	assert.Nil(t, mat.AppendColumn("t"))
+	assert.Nil(t, mat.AppendRow("P", []bool{true, true, false}))
+	assert.Nil(t, mat.AppendRow("Q", []bool{true, false, true}))
+	assert.Nil(t, mat.AppendRow("R", []bool{false, true, true}))
+	return mat
+}
+
+func TestSolveMinPenaltyOvercovers(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newSoftMatrix(t, backend)
+		expected := mat.AsDenseMatrix()
+		// Q alone leaves s uncovered at the same penalty, the first cover found wins
+		cover, err := mat.SolveMinPenalty(map[string]float64{"s": 1, "t": 4})
+		assert.Nil(t, err)
+		assert.Equal(t, &PenaltyCover{Rows: []string{"P", "R"}, Penalty: 1, Overcovered: []string{"s"}}, cover)
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
+}
+
+func TestSolveMinPenaltyLeavesUncovered(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newSoftMatrix(t, backend)
+		cover, err := mat.SolveMinPenalty(map[string]float64{"s": 2, "t": 1})
+		assert.Nil(t, err)
+		assert.Equal(t, &PenaltyCover{Rows: []string{"P"}, Penalty: 1, Uncovered: []string{"t"}}, cover)
+	})
+}
+
+func TestSolveMinPenaltySoftSecondaryColumn(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendSecondaryColumn("cups"))
+		assert.Nil(t, mat.AppendRow("Kim", []bool{false, false, false, true}))
+		assert.Nil(t, mat.AppendRow("Ben", []bool{true, true, true, true}))
+		// uncovered soft secondary columns don't cost anything
+		cover, err := mat.SolveMinPenalty(map[string]float64{"cups": 1})
+		assert.Nil(t, err)
+		assert.Equal(t, &PenaltyCover{Rows: []string{"Amanda", "Chris"}, Penalty: 0}, cover)
+	})
+}
+
+func TestSolveMinPenaltyExactCover(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		cover, err := mat.SolveMinPenalty(map[string]float64{"sour cream": 5})
+		assert.Nil(t, err)
+		assert.Equal(t, &PenaltyCover{Rows: []string{"Amanda", "Chris"}, Penalty: 0}, cover)
+
+		cover, err = mat.SolveMinPenalty(nil)
+		assert.Nil(t, err)
+		assert.Equal(t, &PenaltyCover{Rows: mat.SolveOne(), Penalty: 0}, cover)
+	})
+}
+
+func TestSolveMinPenaltyAssumptions(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newSoftMatrix(t, backend)
+		assert.Nil(t, mat.Push("Q"))
+		cover, err := mat.SolveMinPenalty(map[string]float64{"s": 1, "t": 4})
+		assert.Nil(t, err)
+		assert.Equal(t, &PenaltyCover{Rows: []string{"Q"}, Penalty: 1, Uncovered: []string{"s"}}, cover)
+		assert.Nil(t, mat.Pop())
+	})
+}
+
+func TestSolveMinPenaltyWithoutHardCover(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendColumn("chips"))
+		cover, err := mat.SolveMinPenalty(map[string]float64{"beer": 1})
+		assert.Nil(t, err)
+		assert.Nil(t, cover)
+
+		// unless the empty column is soft
+		cover, err = mat.SolveMinPenalty(map[string]float64{"chips": 3})
+		assert.Nil(t, err)
+		assert.Equal(t, &PenaltyCover{Rows: []string{"Amanda", "Chris"}, Penalty: 3, Uncovered: []string{"chips"}}, cover)
+	})
+}
+
+func TestSolveMinPenaltyErrors(t *testing.T) {
+	mat := NewReadMeExample()
+	_, err := mat.SolveMinPenalty(map[string]float64{"chips": 1})
+	assert.EqualError(t, err, "column chips does not exist")
+	_, err = mat.SolveMinPenalty(map[string]float64{"beer": -1})
+	assert.EqualError(t, err, "penalty of column beer must not be negative")
+}
+
+// the penalty of a set of rows, infinite if it breaks a hard column
+func referencePenalty(c matrixCase, penalties map[int]float64, subset int) float64 {
+	count := make([]int, len(c.primary))
+	for r, row := range c.dense {
+		if subset&(1<<uint(r)) != 0 {
+			for i, v := range row {
+				if v {
+					count[i]++
+				}
+			}
+		}
+	}
+	total := 0.0
+	for i, n := range count {
+		penalty, soft := penalties[i]
+		if !soft {
+			if n > 1 || (n == 0 && c.primary[i]) {
+				return math.Inf(1)
+			}
+			continue
+		}
+		if n > 1 {
+			total += penalty * float64(n-1)
+		} else if n == 0 && c.primary[i] {
+			total += penalty
+		}
+	}
+	return total
+}
+
+func TestSolveMinPenaltyAgreesWithBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	for i := 0; i < 100; i++ {
+		c := newRandomCase(random, 8, 10)
+		penalties := map[int]float64{}
+		named := map[string]float64{}
+		for column := range c.primary {
+			if random.Float64() < 0.5 {
+				penalties[column] = float64(random.Intn(4))
+				named[fmt.Sprintf("c%d", column)] = penalties[column]
+			}
+		}
+		best := math.Inf(1)
+		for subset := 0; subset < 1<<uint(len(c.dense)); subset++ {
+			best = math.Min(best, referencePenalty(c, penalties, subset))
+		}
+
+		forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+			cover, err := c.build(t, backend).SolveMinPenalty(named)
+			assert.Nil(t, err)
+			if math.IsInf(best, 1) {
+				assert.Nil(t, cover)
+				return
+			}
+			assert.Equal(t, best, cover.Penalty)
+			subset := 0
+			for _, row := range cover.Rows {
+				var r int
+				_, _ = fmt.Sscanf(row, "r%d", &r)
+				subset |= 1 << uint(r)
+			}
+			assert.Equal(t, best, referencePenalty(c, penalties, subset))
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+// matrixCase is a small matrix that the reference solver can still enumerate
+type matrixCase struct {
+	dense   [][]bool
+	primary []bool
+}
+
+// newRandomCase generates a matrix with up to maxColumns columns, a quarter of them secondary, and up to maxRows
+// rows of a random density
+func newRandomCase(random *rand.Rand, maxColumns int, maxRows int) matrixCase {
+	c := matrixCase{primary: make([]bool, 1+random.Intn(maxColumns))}
+	for i := range c.primary {
+		c.primary[i] = random.Float64() >= 0.25
+	}
+	density := 0.1 + 0.5*random.Float64()
+	c.dense = make([][]bool, random.Intn(maxRows+1))
+	for r := range c.dense {
+		c.dense[r] = make([]bool, len(c.primary))
+		for i := range c.dense[r] {
+			c.dense[r][i] = random.Float64() < density
+		}
+	}
+	return c
+}
+
+func (c matrixCase) build(t *testing.T, options ...MatrixOption) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix(options...)
+	for i, primary := range c.primary {
+		if primary {
+			assert.Nil(t, mat.AppendColumn(fmt.Sprintf("c%d", i)))
+		} else {
+			assert.Nil(t, mat.AppendSecondaryColumn(fmt.Sprintf("c%d", i)))
+		}
+	}
+	for r, row := range c.dense {
+		assert.Nil(t, mat.AppendRow(fmt.Sprintf("r%d", r), row))
+	}
+	return mat
+}
+
+// referenceSolutions tries every subset of the rows and keeps the exact covers. Rows without primary columns are
+// left out, just like the search only chooses rows to cover a primary column.
+func (c matrixCase) referenceSolutions() [][]string {
+	var solutions [][]string
+	for subset := 0; subset < 1<<uint(len(c.dense)); subset++ {
+		used := make([]int, len(c.primary))
+		var rows []string
+		valid := true
+		for r, row := range c.dense {
+			if subset&(1<<uint(r)) == 0 {
+				continue
+			}
+			optional := true
+			for i, v := range row {
+				if v {
+					used[i]++
+					optional = optional && !c.primary[i]
+				}
+			}
+			valid = valid && !optional
+			rows = append(rows, fmt.Sprintf("r%d", r))
+		}
+		for i, n := range used {
+			valid = valid && n <= 1 && (n == 1 || !c.primary[i])
+		}
+		if valid {
+			solutions = append(solutions, append([]string{}, rows...))
+		}
+	}
+	return solutions
+}
+
+// assertAgreesWithReference solves the case with every backend and decomposition, the matrix needs to be restored
+// after every solve
+func assertAgreesWithReference(t *testing.T, c matrixCase, options ...MatrixOption) {
+	expected := c.referenceSolutions()
+	for _, backend := range allBackends {
+		for _, decomposition := range []Decomposition{NoDecomposition, DecomposeUpFront, DecomposeDuringSearch} {
+			mat := c.build(t, append([]MatrixOption{WithBackend(backend), WithDecomposition(decomposition)}, options...)...)
+			dense := mat.AsDenseMatrix()
+			message := fmt.Sprintf("%s with decomposition %d on matrix %v, primary %v",
+				backend, decomposition, c.dense, c.primary)
+
+			assertSameSolutions(t, expected, mat.Solve())
+			assert.Equal(t, dense, mat.AsDenseMatrix(), message)
+
+			solution := mat.SolveOne()
+			if len(expected) == 0 {
+				assert.Nil(t, solution, message)
+			} else {
+				assert.Contains(t, normalizeSolutions(expected), normalizeSolutions([][]string{solution})[0], message)
+			}
+			assert.Equal(t, dense, mat.AsDenseMatrix(), message)
+
+			assert.Equal(t, len(expected), mat.Count(), message)
+			assert.Equal(t, dense, mat.AsDenseMatrix(), message)
+
+			check := mat.CheckUnique()
+			if len(expected) < 2 {
+				assert.Equal(t, Uniqueness(len(expected)), check.Uniqueness, message)
+				assert.Equal(t, normalizeSolutions(expected), normalizeSolutions(check.Witnesses), message)
+			} else {
+				assert.Equal(t, UniquenessMultiple, check.Uniqueness, message)
+				assert.NotEmpty(t, check.DivergingColumn, message)
+			}
+			assert.Equal(t, dense, mat.AsDenseMatrix(), message)
+		}
+	}
+}
+
+func TestReferenceSolutions(t *testing.T) {
+	c := matrixCase{
+		primary: []bool{true, true, false},
+		dense: [][]bool{
+			{true, false, false},
+			{false, true, true},
+			{true, true, false},
+			{false, false, false},
+			{false, false, true},
+		},
+	}
+	assert.Equal(t, [][]string{{"r0", "r1"}, {"r2"}}, c.referenceSolutions())
+
+	assert.Equal(t, [][]string{{}}, matrixCase{}.referenceSolutions())
+}
+
+func TestAgreesWithReference(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		assertAgreesWithReference(t, newRandomCase(random, 8, 10))
+	}
+}
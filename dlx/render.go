@@ -0,0 +1,120 @@
+package dlx
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+const (
+	svgCellSize  = 20
+	svgCharWidth = 8
+	svgPadding   = 6
+)
+
+// matrixSnapshot is the full structure of a matrix including the cells that are currently hidden by covered columns
+type matrixSnapshot struct {
+	columns        []string
+	rows           []string
+	cells          [][]bool
+	coveredColumns []bool
+	coveredRows    []bool
+}
+
+func writeSVG(s matrixSnapshot, writer io.Writer) error {
+	labelWidth := maxLength(s.rows)*svgCharWidth + 2*svgPadding
+	labelHeight := maxLength(s.columns)*svgCharWidth + 2*svgPadding
+	width := labelWidth + len(s.columns)*svgCellSize
+	height := labelHeight + len(s.rows)*svgCellSize
+
+	sb := &strings.Builder{}
+	sb.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" "+
+		"font-family=\"monospace\" font-size=\"12\">\n", width, height))
+	for c, column := range s.columns {
+		x := labelWidth + c*svgCellSize + svgCellSize/2 + 4
+		sb.WriteString(fmt.Sprintf("  <text transform=\"translate(%d,%d) rotate(-90)\" fill=\"%s\">%s</text>\n",
+			x, labelHeight-svgPadding, labelColor(s.coveredColumns[c]), html.EscapeString(column)))
+	}
+
+	for r, row := range s.rows {
+		y := labelHeight + r*svgCellSize + svgCellSize/2 + 4
+		sb.WriteString(fmt.Sprintf("  <text x=\"%d\" y=\"%d\" text-anchor=\"end\" fill=\"%s\">%s</text>\n",
+			labelWidth-svgPadding, y, labelColor(s.coveredRows[r]), html.EscapeString(row)))
+		for c := range s.columns {
+			sb.WriteString(fmt.Sprintf("  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\" stroke=\"#cccccc\"/>\n",
+				labelWidth+c*svgCellSize, labelHeight+r*svgCellSize, svgCellSize, svgCellSize,
+				cellColor(s.cells[r][c], s.coveredRows[r] || s.coveredColumns[c])))
+		}
+	}
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+// PBM is black and white only, that's why covered rows and columns are left blank instead of greyed out
+func writePBM(s matrixSnapshot, writer io.Writer) error {
+	sb := &strings.Builder{}
+	sb.WriteString("P1\n")
+	sb.WriteString(fmt.Sprintf("# columns: %s\n", pbmComment(s.columns)))
+	sb.WriteString(fmt.Sprintf("# rows: %s\n", pbmComment(s.rows)))
+	sb.WriteString(fmt.Sprintf("%d %d\n", len(s.columns), len(s.rows)))
+	for r := range s.rows {
+		for c := range s.columns {
+			if c > 0 {
+				sb.WriteString(" ")
+			}
+			if s.cells[r][c] && !s.coveredRows[r] && !s.coveredColumns[c] {
+				sb.WriteString("1")
+			} else {
+				sb.WriteString("0")
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+// pbmComment joins the identifiers for a comment line, line breaks would end the comment and corrupt the image
+func pbmComment(identifiers []string) string {
+	escaped := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		identifier = strings.ReplaceAll(identifier, `\`, `\\`)
+		identifier = strings.ReplaceAll(identifier, "\n", `\n`)
+		escaped[i] = strings.ReplaceAll(identifier, "\r", `\r`)
+	}
+	return strings.Join(escaped, ", ")
+}
+
+func labelColor(covered bool) string {
+	if covered {
+		return "#aaaaaa"
+	}
+	return "#000000"
+}
+
+func cellColor(set, covered bool) string {
+	switch {
+	case set && covered:
+		return "#aaaaaa"
+	case set:
+		return "#000000"
+	case covered:
+		return "#eeeeee"
+	default:
+		return "#ffffff"
+	}
+}
+
+func maxLength(identifiers []string) int {
+	max := 0
+	for _, id := range identifiers {
+		if len(id) > max {
+			max = len(id)
+		}
+	}
+	return max
+}
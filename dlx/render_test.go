@@ -0,0 +1,137 @@
+package dlx
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newCoveredRenderExample(t *testing.T) DancingLinksMatrixI {
+	mat := NewDancingLinkMatrix()
+	assert.Nil(t, mat.AppendColumn("a"))
+	assert.Nil(t, mat.AppendColumn("b"))
+	assert.Nil(t, mat.AppendRow("X", []bool{true, true}))
+	assert.Nil(t, mat.AppendRow("Y", []bool{false, true}))
+	assert.Nil(t, mat.CoverColumn(0))
+	return mat
+}
+
+func TestWriteSVG(t *testing.T) {
+	mat := newCoveredRenderExample(t)
+	builder := &strings.Builder{}
+	assert.Nil(t, mat.WriteSVG(builder))
+	assert.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="60" height="60" font-family="monospace" font-size="12">
+  <text transform="translate(34,14) rotate(-90)" fill="#aaaaaa">a</text>
+  <text transform="translate(54,14) rotate(-90)" fill="#000000">b</text>
+  <text x="14" y="34" text-anchor="end" fill="#aaaaaa">X</text>
+  <rect x="20" y="20" width="20" height="20" fill="#aaaaaa" stroke="#cccccc"/>
+  <rect x="40" y="20" width="20" height="20" fill="#aaaaaa" stroke="#cccccc"/>
+  <text x="14" y="54" text-anchor="end" fill="#000000">Y</text>
+  <rect x="20" y="40" width="20" height="20" fill="#eeeeee" stroke="#cccccc"/>
+  <rect x="40" y="40" width="20" height="20" fill="#000000" stroke="#cccccc"/>
+</svg>
+`, builder.String())
+}
+
+func TestWriteSVGEscapesLabels(t *testing.T) {
+	mat := NewDancingLinkMatrix()
+	assert.Nil(t, mat.AppendColumn("<a>"))
+	assert.Nil(t, mat.AppendRow("X&Y", []bool{true}))
+	builder := &strings.Builder{}
+	assert.Nil(t, mat.WriteSVG(builder))
+	assert.Contains(t, builder.String(), ">&lt;a&gt;</text>")
+	assert.Contains(t, builder.String(), ">X&amp;Y</text>")
+}
+
+func TestWritePBMEscapesComments(t *testing.T) {
+	mat := NewDancingLinkMatrix()
+	assert.Nil(t, mat.AppendColumn("a\n1 1"))
+	assert.Nil(t, mat.AppendRow("X\\Y\r", []bool{true}))
+	builder := &strings.Builder{}
+	assert.Nil(t, mat.WritePBM(builder))
+	assert.Equal(t, `P1
+# columns: a\n1 1
+# rows: X\\Y\r
+1 1
+1
+`, builder.String())
+}
+
+func TestWritePBM(t *testing.T) {
+	mat := NewWikipediaExampleMatrix(t)
+	builder := &strings.Builder{}
+	assert.Nil(t, mat.WritePBM(builder))
+	assert.Equal(t, `P1
+# columns: 1, 2, 3, 4, 5, 6, 7
+# rows: A, B, C, D, E, F
+7 6
+1 0 0 1 0 0 1
+1 0 0 1 0 0 0
+0 0 0 1 1 0 1
+0 0 1 0 1 1 0
+0 1 1 0 0 1 1
+0 1 0 0 0 0 1
+`, builder.String())
+
+	assert.Nil(t, mat.CoverColumn(3))
+	builder = &strings.Builder{}
+	assert.Nil(t, mat.WritePBM(builder))
+	assert.Equal(t, `P1
+# columns: 1, 2, 3, 4, 5, 6, 7
+# rows: A, B, C, D, E, F
+7 6
+0 0 0 0 0 0 0
+0 0 0 0 0 0 0
+0 0 0 0 0 0 0
+0 0 1 0 1 1 0
+0 1 1 0 0 1 1
+0 1 0 0 0 0 1
+`, builder.String())
+}
+
+// the renderers only need a plain writer, like a compressing writer or a hash that has no WriteString
+func TestRenderingToPlainWriter(t *testing.T) {
+	mat := NewReadMeExample()
+	for name, write := range map[string]func(writer io.Writer) error{
+		"svg":   mat.WriteSVG,
+		"pbm":   mat.WritePBM,
+		"links": mat.(*DancingLinksMatrix).WriteLinksDOT,
+	} {
+		expected := &strings.Builder{}
+		assert.Nil(t, write(expected), name)
+		actual := &bytes.Buffer{}
+		assert.Nil(t, write(struct{ io.Writer }{actual}), name)
+		assert.Equal(t, expected.String(), actual.String(), name)
+	}
+}
+
+func TestSnapshotKeepsCoveredCells(t *testing.T) {
+	mat := NewWikipediaExampleMatrix(t)
+	assert.Nil(t, mat.CoverColumn(3))
+	assert.Nil(t, mat.CoverColumn(2))
+	s := mat.(*DancingLinksMatrix).snapshot()
+	assert.Equal(t, NewWikipediaExampleMatrix(t).AsDenseMatrix(), s.cells)
+	assert.Equal(t, []bool{false, false, true, true, false, false, false}, s.coveredColumns)
+	assert.Equal(t, []bool{true, true, true, true, true, false}, s.coveredRows)
+}
+
+func TestWriteLinksDOT(t *testing.T) {
+	mat := newCoveredRenderExample(t)
+	builder := &strings.Builder{}
+	assert.Nil(t, mat.(*DancingLinksMatrix).WriteLinksDOT(builder))
+	dot := builder.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph links {\n"))
+	// the covered header and the unlinked node are greyed out
+	assert.Contains(t, dot, `c0 [label="a", color=grey, fontcolor=grey];`)
+	assert.Contains(t, dot, `n0_1 [label="X\nb", color=grey, fontcolor=grey];`)
+	assert.Contains(t, dot, `n1_1 [label="Y\nb"];`)
+	// the head skips the covered column, but the column still points back to it
+	assert.Contains(t, dot, `head -> c1 [label="R"];`)
+	assert.Contains(t, dot, `c0 -> head [label="L", style=dashed, constraint=false];`)
+	// the unlinked node still points to its old neighbours
+	assert.Contains(t, dot, `n0_1 -> n1_1 [label="D"];`)
+	assert.Contains(t, dot, `c1 -> n1_1 [label="D"];`)
+	assert.Contains(t, dot, "{ rank=same; n0_0; n0_1; } // X")
+}
@@ -0,0 +1,71 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+func TestRowPriorities(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		for _, decomposition := range []Decomposition{NoDecomposition, DecomposeUpFront, DecomposeDuringSearch} {
+			mat := NewReadMeExample(backend, WithDecomposition(decomposition), WithRowPriorities(map[string]int{"Jen": 1}))
+			assert.Equal(t, []string{"Jen"}, mat.SolveOne())
+			assert.Equal(t, [][]string{{"Jen"}, {"Amanda", "Chris"}}, mat.Solve())
+
+			mat = NewReadMeExample(backend, WithDecomposition(decomposition), WithRowPriorities(map[string]int{"Jen": -1}))
+			assert.Equal(t, []string{"Amanda", "Chris"}, mat.SolveOne())
+		}
+	})
+}
+
+func TestRowOrdererRanksTheChosenColumn(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		var calls [][]string
+		mat := NewReadMeExample(backend, WithRowOrderer(func(column string, row string) int {
+			calls = append(calls, []string{column, row})
+			return 0
+		}))
+		assert.Equal(t, []string{"Amanda", "Chris"}, mat.SolveOne())
+		// nachos has the fewest rows, equal ranks keep the order of the rows
+		assert.Equal(t, []string{"nachos", "Amanda"}, calls[0])
+		assert.Equal(t, []string{"nachos", "Jen"}, calls[1])
+		assert.Equal(t, []string{"sour cream", "Chris"}, calls[2])
+	})
+}
+
+func TestRowOrdererPagesAndIterates(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		// the queens on the rightmost columns first
+		mat := newNQueensMatrix(6, backend, WithRowOrderer(func(column string, row string) int {
+			return int(row[len(row)-1])
+		}))
+		all := mat.Solve()
+		assert.Equal(t, 4, len(all))
+
+		it := mat.Iterate()
+		first, ok := it.Next()
+		assert.True(t, ok)
+		it.Close()
+		assert.Equal(t, all[0], first)
+		assert.Equal(t, all[0], mat.SolveOne())
+
+		page, err := mat.SolveRange(1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, all[1:3], page.Solutions)
+	})
+}
+
+func TestRowOrdererAgreesWithReference(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	for i := 0; i < 50; i++ {
+		ranks := map[string]int{}
+		for r := 0; r < 10; r++ {
+			ranks[fmt.Sprintf("r%d", r)] = random.Intn(3)
+		}
+		assertAgreesWithReference(t, newRandomCase(random, 8, 10), WithRowOrderer(func(column string, row string) int {
+			return ranks[row] + len(column)
+		}))
+	}
+}
@@ -0,0 +1,150 @@
+package dlx
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// SearchTrace records the search tree that is explored while solving a matrix. Every node in the tree is a row that
+// was tried to cover the chosen column, its children are the rows tried on the next level of the search.
+type SearchTrace struct {
+	maxNodes int
+	nodes    []traceNode
+	omitted  int
+	// ids of the nodes on the current search path, -1 marks nodes that were omitted because of the cap
+	path []int
+}
+
+type traceNode struct {
+	parent    int
+	column    string
+	row       string
+	deadEnd   bool // the column had no rows left to try
+	solution  bool // the row completed a cover
+	solutions int  // number of solutions found in the subtree
+}
+
+func newSearchTrace(maxNodes int) *SearchTrace {
+	t := &SearchTrace{maxNodes: maxNodes}
+	t.reset()
+	return t
+}
+
+// NumNodes returns the number of recorded nodes including the root of the search.
+func (t *SearchTrace) NumNodes() int {
+	return len(t.nodes)
+}
+
+// NumOmitted returns the number of nodes that were explored but not recorded, because the trace was full.
+func (t *SearchTrace) NumOmitted() int {
+	return t.omitted
+}
+
+// WriteDOT writes the recorded search tree in the Graphviz DOT format. Failed branches are red, rows that
+// completed a cover are green and branches that contain at least one solution are drawn with a green outline.
+func (t *SearchTrace) WriteDOT(writer io.Writer) error {
+	sb := &strings.Builder{}
+	sb.WriteString("digraph search {\n")
+	sb.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+	for i, n := range t.nodes {
+		var label, attributes string
+		switch {
+		case i == 0:
+			label = "start"
+		case n.deadEnd:
+			label = fmt.Sprintf("column: %s\nno rows left", n.column)
+		default:
+			label = fmt.Sprintf("column: %s\nrow: %s", n.column, n.row)
+		}
+
+		switch {
+		case n.solution:
+			attributes = ", style=filled, fillcolor=palegreen"
+		case n.solutions > 0:
+			attributes = ", color=green"
+		case i > 0:
+			attributes = ", color=red"
+		}
+		sb.WriteString(fmt.Sprintf("  n%d [label=%s%s];\n", i, dotQuote(label), attributes))
+		if i > 0 {
+			sb.WriteString(fmt.Sprintf("  n%d -> n%d;\n", n.parent, i))
+		}
+	}
+
+	if t.omitted > 0 {
+		sb.WriteString(fmt.Sprintf("  omitted [label=%s, shape=note];\n",
+			dotQuote(fmt.Sprintf("%d more nodes omitted", t.omitted))))
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+func (t *SearchTrace) reset() {
+	if t == nil {
+		return
+	}
+	t.nodes = []traceNode{{parent: -1}}
+	t.path = []int{0}
+	t.omitted = 0
+}
+
+// enter records that the given row is tried to cover the given column
+func (t *SearchTrace) enter(column, row string) {
+	if t == nil {
+		return
+	}
+	t.path = append(t.path, t.add(traceNode{column: column, row: row}))
+}
+
+// leave closes the last entered row again
+func (t *SearchTrace) leave() {
+	if t == nil {
+		return
+	}
+	t.path = t.path[:len(t.path)-1]
+}
+
+// deadEnd records that the given column was chosen, but had no rows left to try
+func (t *SearchTrace) deadEnd(column string) {
+	if t == nil {
+		return
+	}
+	t.add(traceNode{column: column, deadEnd: true})
+}
+
+// solution records that the current path is a solution
+func (t *SearchTrace) solution() {
+	if t == nil {
+		return
+	}
+	last := t.path[len(t.path)-1]
+	if last >= 0 {
+		t.nodes[last].solution = true
+	}
+	for _, id := range t.path {
+		if id >= 0 {
+			t.nodes[id].solutions++
+		}
+	}
+}
+
+func (t *SearchTrace) add(node traceNode) int {
+	parent := t.path[len(t.path)-1]
+	if parent < 0 || len(t.nodes) >= t.maxNodes {
+		t.omitted++
+		return -1
+	}
+	node.parent = parent
+	t.nodes = append(t.nodes, node)
+	return len(t.nodes) - 1
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
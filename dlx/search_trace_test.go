@@ -0,0 +1,91 @@
+package dlx
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTraceReadMeExample(t *testing.T) {
+	mat := NewReadMeExample()
+	trace := mat.TraceSearch(100)
+	assert.Equal(t, 2, len(mat.Solve()))
+	assert.Equal(t, 4, trace.NumNodes())
+	assert.Equal(t, 0, trace.NumOmitted())
+
+	builder := &strings.Builder{}
+	assert.Nil(t, trace.WriteDOT(builder))
+	assert.Equal(t, `digraph search {
+  node [shape=box, fontname="Helvetica"];
+  n0 [label="start", color=green];
+  n1 [label="column: nachos\nrow: Amanda", color=green];
+  n0 -> n1;
+  n2 [label="column: sour cream\nrow: Chris", style=filled, fillcolor=palegreen];
+  n1 -> n2;
+  n3 [label="column: nachos\nrow: Jen", style=filled, fillcolor=palegreen];
+  n0 -> n3;
+}
+`, builder.String())
+}
+
+func TestTraceWritesToPlainWriter(t *testing.T) {
+	mat := NewReadMeExample()
+	trace := mat.TraceSearch(100)
+	mat.Solve()
+	expected := &strings.Builder{}
+	assert.Nil(t, trace.WriteDOT(expected))
+	actual := &bytes.Buffer{}
+	assert.Nil(t, trace.WriteDOT(struct{ io.Writer }{actual}))
+	assert.Equal(t, expected.String(), actual.String())
+}
+
+func TestTraceDeadEnds(t *testing.T) {
+	mat := NewWikipediaExampleMatrix(t)
+	trace := mat.TraceSearch(100)
+	assert.Equal(t, 1, len(mat.Solve()))
+	assert.Equal(t, 6, trace.NumNodes())
+
+	builder := &strings.Builder{}
+	assert.Nil(t, trace.WriteDOT(builder))
+	assert.Contains(t, builder.String(), `n1 [label="column: 1\nrow: A", color=red];`)
+	assert.Contains(t, builder.String(), `n2 [label="column: 2\nno rows left", color=red];`)
+	assert.Contains(t, builder.String(), `n3 [label="column: 1\nrow: B", color=green];`)
+	assert.Contains(t, builder.String(), `n5 [label="column: 2\nrow: F", style=filled, fillcolor=palegreen];`)
+}
+
+func TestTraceIsCapped(t *testing.T) {
+	mat := NewWikipediaExampleMatrix(t)
+	trace := mat.TraceSearch(3)
+	assert.Equal(t, 1, len(mat.Solve()))
+	assert.Equal(t, 3, trace.NumNodes())
+	assert.Equal(t, 3, trace.NumOmitted())
+
+	builder := &strings.Builder{}
+	assert.Nil(t, trace.WriteDOT(builder))
+	assert.Contains(t, builder.String(), `omitted [label="3 more nodes omitted", shape=note];`)
+	// the root still knows that a solution was found underneath
+	assert.Contains(t, builder.String(), `n0 [label="start", color=green];`)
+}
+
+func TestTraceIsResetOnEverySolve(t *testing.T) {
+	mat := NewReadMeExample()
+	trace := mat.TraceSearch(100)
+	assert.NotNil(t, mat.SolveOne())
+	assert.Equal(t, 3, trace.NumNodes())
+	assert.Equal(t, 2, len(mat.Solve()))
+	assert.Equal(t, 4, trace.NumNodes())
+}
+
+func TestTraceCanBeDisabled(t *testing.T) {
+	mat := NewReadMeExample()
+	trace := mat.TraceSearch(100)
+	assert.Nil(t, mat.TraceSearch(0))
+	assert.Equal(t, 2, len(mat.Solve()))
+	assert.Equal(t, 1, trace.NumNodes())
+}
+
+func TestDotQuote(t *testing.T) {
+	assert.Equal(t, `"a\"b\\c\nd"`, dotQuote("a\"b\\c\nd"))
+}
@@ -0,0 +1,317 @@
+package dlx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// the binary encoding starts with the magic bytes and the version, other versions are rejected
+const (
+	binaryMagic   = "DLX"
+	binaryVersion = 1
+)
+
+const (
+	binaryStepCover = 0
+	binaryStepPush  = 1
+)
+
+// matrixModel is the backend independent form of a matrix that both encodings are built on
+type matrixModel struct {
+	Columns []modelColumn `json:"columns"`
+	Rows    []modelRow    `json:"rows"`
+	// the covered columns and the pushed assumptions in the order they need to be replayed
+	Steps []modelStep `json:"steps,omitempty"`
+}
+
+type modelColumn struct {
+	Name    string `json:"name"`
+	Primary bool   `json:"primary"`
+}
+
+type modelRow struct {
+	Name string `json:"name"`
+	// the sorted indices of the columns the row is true in
+	Columns []int `json:"columns"`
+}
+
+// modelStep either covers a column or pushes rows, a step without a column pushes
+type modelStep struct {
+	Cover *int  `json:"cover,omitempty"`
+	Push  []int `json:"push,omitempty"`
+}
+
+// modelTarget is a freshly created matrix that a model is restored into
+type modelTarget interface {
+	DancingLinksMatrixI
+	pushRows(rowIndices []int) error
+}
+
+func newMatrixModel(backend coverBackend, a *assumptions) *matrixModel {
+	model := &matrixModel{}
+	for c, column := range backend.Columns() {
+		model.Columns = append(model.Columns, modelColumn{Name: column, Primary: backend.isPrimary(c)})
+	}
+	for r, row := range backend.Rows() {
+		model.Rows = append(model.Rows, modelRow{Name: row, Columns: append([]int{}, backend.rowColumns(r)...)})
+	}
+	model.Steps = coverSteps(a)
+	return model
+}
+
+// coverSteps replays the covering in the order it happened: the columns covered on their own before a frame come
+// before the frame, the ones covered after the last frame come last. A covered row stays visible in the column that
+// hid it, so any other order could end up in a different state.
+func coverSteps(a *assumptions) []modelStep {
+	var steps []modelStep
+	next := 0
+	appendCovers := func(until int) {
+		for ; next < until; next++ {
+			column := a.covers[next]
+			steps = append(steps, modelStep{Cover: &column})
+		}
+	}
+	for _, frame := range a.frames {
+		appendCovers(frame.numCovers)
+		steps = append(steps, modelStep{Push: append([]int{}, frame.rows...)})
+	}
+	appendCovers(len(a.covers))
+	return steps
+}
+
+func (model *matrixModel) restore(target modelTarget) error {
+	for _, column := range model.Columns {
+		var err error
+		if column.Primary {
+			err = target.AppendColumn(column.Name)
+		} else {
+			err = target.AppendSecondaryColumn(column.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, row := range model.Rows {
+		rowValues := make([]bool, len(model.Columns))
+		for _, c := range row.Columns {
+			if c < 0 || c >= len(model.Columns) {
+				return fmt.Errorf("row %s references column %d, but there are only %d columns",
+					row.Name, c, len(model.Columns))
+			}
+			rowValues[c] = true
+		}
+		if err := target.AppendRow(row.Name, rowValues); err != nil {
+			return err
+		}
+	}
+
+	for _, step := range model.Steps {
+		if step.Cover != nil {
+			if err := target.CoverColumn(*step.Cover); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, r := range step.Push {
+			if r < 0 || r >= len(model.Rows) {
+				return fmt.Errorf("assumption references row %d, but there are only %d rows", r, len(model.Rows))
+			}
+		}
+		if err := target.pushRows(step.Push); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func marshalModelJSON(backend coverBackend, a *assumptions) ([]byte, error) {
+	return json.Marshal(newMatrixModel(backend, a))
+}
+
+func unmarshalModelJSON(data []byte, target modelTarget) error {
+	model := &matrixModel{}
+	if err := json.Unmarshal(data, model); err != nil {
+		return err
+	}
+	return model.restore(target)
+}
+
+// marshalModelBinary writes the magic bytes, the version and then the model with all numbers as varints. The column
+// indices of a row are sorted, so every index is stored as the difference to its predecessor.
+func marshalModelBinary(backend coverBackend, a *assumptions) ([]byte, error) {
+	model := newMatrixModel(backend, a)
+	w := &binaryWriter{}
+	w.buf.WriteString(binaryMagic)
+	w.buf.WriteByte(binaryVersion)
+
+	w.uvarint(len(model.Columns))
+	for _, column := range model.Columns {
+		w.string(column.Name)
+		if column.Primary {
+			w.buf.WriteByte(1)
+		} else {
+			w.buf.WriteByte(0)
+		}
+	}
+
+	w.uvarint(len(model.Rows))
+	for _, row := range model.Rows {
+		w.string(row.Name)
+		w.uvarint(len(row.Columns))
+		previous := 0
+		for _, c := range row.Columns {
+			w.uvarint(c - previous)
+			previous = c
+		}
+	}
+
+	w.uvarint(len(model.Steps))
+	for _, step := range model.Steps {
+		if step.Cover != nil {
+			w.buf.WriteByte(binaryStepCover)
+			w.uvarint(*step.Cover)
+			continue
+		}
+		w.buf.WriteByte(binaryStepPush)
+		w.uvarint(len(step.Push))
+		for _, r := range step.Push {
+			w.uvarint(r)
+		}
+	}
+	return w.buf.Bytes(), nil
+}
+
+func unmarshalModelBinary(data []byte, target modelTarget) error {
+	if !bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return fmt.Errorf("not a binary encoded matrix")
+	}
+	r := &binaryReader{reader: bytes.NewReader(data[len(binaryMagic):])}
+	if version := r.byte(); r.err == nil && version != binaryVersion {
+		return fmt.Errorf("unsupported binary matrix version %d", version)
+	}
+
+	model := &matrixModel{}
+	numColumns := r.uvarint()
+	for i := 0; r.err == nil && i < numColumns; i++ {
+		name := r.string()
+		model.Columns = append(model.Columns, modelColumn{Name: name, Primary: r.byte() == 1})
+	}
+
+	numRows := r.uvarint()
+	for i := 0; r.err == nil && i < numRows; i++ {
+		row := modelRow{Name: r.string()}
+		numIndices := r.uvarint()
+		previous := 0
+		for j := 0; r.err == nil && j < numIndices; j++ {
+			previous += r.uvarint()
+			row.Columns = append(row.Columns, previous)
+		}
+		model.Rows = append(model.Rows, row)
+	}
+
+	numSteps := r.uvarint()
+	for i := 0; r.err == nil && i < numSteps; i++ {
+		step := modelStep{}
+		switch kind := r.byte(); kind {
+		case binaryStepCover:
+			column := r.uvarint()
+			step.Cover = &column
+		case binaryStepPush:
+			numPushed := r.uvarint()
+			for j := 0; r.err == nil && j < numPushed; j++ {
+				step.Push = append(step.Push, r.uvarint())
+			}
+		default:
+			if r.err == nil {
+				r.err = fmt.Errorf("unknown step %d", kind)
+			}
+		}
+		model.Steps = append(model.Steps, step)
+	}
+
+	if r.err != nil {
+		return fmt.Errorf("invalid binary matrix: %v", r.err)
+	}
+	if r.reader.Len() > 0 {
+		return fmt.Errorf("invalid binary matrix: %d unexpected trailing bytes", r.reader.Len())
+	}
+	return model.restore(target)
+}
+
+type binaryWriter struct {
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *binaryWriter) uvarint(v int) {
+	n := binary.PutUvarint(w.scratch[:], uint64(v))
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *binaryWriter) string(s string) {
+	w.uvarint(len(s))
+	w.buf.WriteString(s)
+}
+
+// binaryReader keeps the first error, all reads after it return zero values
+type binaryReader struct {
+	reader *bytes.Reader
+	err    error
+}
+
+func (r *binaryReader) fail(err error) {
+	if r.err != nil {
+		return
+	}
+	if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+		err = fmt.Errorf("unexpected end of data")
+	}
+	r.err = err
+}
+
+func (r *binaryReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	b, err := r.reader.ReadByte()
+	if err != nil {
+		r.fail(err)
+	}
+	return b
+}
+
+func (r *binaryReader) uvarint() int {
+	if r.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		r.fail(err)
+		return 0
+	}
+	// every index and length is bounded by the data that follows it, larger values can only come from corrupt data
+	if v > uint64(r.reader.Size()) {
+		r.fail(fmt.Errorf("value %d is out of range", v))
+		return 0
+	}
+	return int(v)
+}
+
+func (r *binaryReader) string() string {
+	n := r.uvarint()
+	if r.err != nil {
+		return ""
+	}
+	if n > r.reader.Len() {
+		r.fail(io.EOF)
+		return ""
+	}
+	b := make([]byte, n)
+	_, _ = r.reader.Read(b)
+	return string(b)
+}
@@ -0,0 +1,190 @@
+package dlx
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// the encodings under test, each restores into a fresh matrix created with the given options
+var encodings = map[string]func(t *testing.T, mat DancingLinksMatrixI, options ...MatrixOption) DancingLinksMatrixI{
+	"JSON": func(t *testing.T, mat DancingLinksMatrixI, options ...MatrixOption) DancingLinksMatrixI {
+		data, err := json.Marshal(mat)
+		assert.Nil(t, err)
+		restored := NewDancingLinkMatrix(options...)
+		assert.Nil(t, json.Unmarshal(data, restored))
+		return restored
+	},
+	"Binary": func(t *testing.T, mat DancingLinksMatrixI, options ...MatrixOption) DancingLinksMatrixI {
+		data, err := mat.MarshalBinary()
+		assert.Nil(t, err)
+		restored := NewDancingLinkMatrix(options...)
+		assert.Nil(t, restored.UnmarshalBinary(data))
+		return restored
+	},
+}
+
+func assertSameMatrix(t *testing.T, expected DancingLinksMatrixI, actual DancingLinksMatrixI) {
+	assert.Equal(t, expected.Columns(), actual.Columns())
+	assert.Equal(t, expected.Rows(), actual.Rows())
+	assert.Equal(t, expected.AsDenseMatrix(), actual.AsDenseMatrix())
+	assert.Equal(t, expected.NumUncoveredColumns(), actual.NumUncoveredColumns())
+	assert.Equal(t, expected.NumAssumptions(), actual.NumAssumptions())
+	assertSameSolutions(t, expected.Solve(), actual.Solve())
+}
+
+func TestMarshalJSON(t *testing.T) {
+	mat := NewReadMeExample()
+	assert.Nil(t, mat.AppendSecondaryColumn("chips"))
+	assert.Nil(t, mat.AppendRow("Nobody", []bool{false, false, false, false}))
+	data, err := json.Marshal(mat)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"columns":[{"name":"beer","primary":true},{"name":"nachos","primary":true},`+
+		`{"name":"sour cream","primary":true},{"name":"chips","primary":false}],"rows":[`+
+		`{"name":"Jack","columns":[0]},{"name":"Amanda","columns":[0,1]},{"name":"Chris","columns":[2]},`+
+		`{"name":"Jen","columns":[0,1,2]},{"name":"Nobody","columns":[]}]}`, string(data))
+
+	assert.Nil(t, mat.CoverColumn(2))
+	assert.Nil(t, mat.Push("Jack"))
+	data, err = json.Marshal(mat)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"steps":[{"cover":2},{"push":[0]}]`)
+
+	assert.Nil(t, mat.CoverColumn(1))
+	data, err = json.Marshal(mat)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"steps":[{"cover":2},{"push":[0]},{"cover":1}]`)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for name, encode := range encodings {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+				mat := newNQueensMatrix(6, backend)
+				assertSameMatrix(t, mat, encode(t, mat, backend))
+
+				// every backend reads what the others wrote
+				for _, other := range allBackends {
+					assertSameMatrix(t, mat, encode(t, mat, WithBackend(other)))
+				}
+			})
+		})
+	}
+}
+
+func TestRoundTripWithCoveredState(t *testing.T) {
+	for name, encode := range encodings {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+				mat := newNQueensMatrix(6, backend)
+				expected := mat.AsDenseMatrix()
+				assert.Nil(t, mat.Push("queen_0_1"))
+				assert.Nil(t, mat.CoverColumn(11))
+				assert.Nil(t, mat.CoverColumn(8))
+				assert.Nil(t, mat.Push("queen_1_3", "queen_5_4"))
+				assert.Nil(t, mat.Push())
+
+				restored := encode(t, mat, backend)
+				assertSameMatrix(t, mat, restored)
+
+				// the restored matrix can be unwound just like the original
+				for restored.NumAssumptions() > 1 {
+					assert.Nil(t, restored.Pop())
+					assert.Nil(t, mat.Pop())
+					assertSameMatrix(t, mat, restored)
+				}
+				assert.Nil(t, restored.UncoverColumn(8))
+				assert.Nil(t, restored.UncoverColumn(11))
+				assert.Nil(t, restored.Pop())
+				assert.Equal(t, expected, restored.AsDenseMatrix())
+			})
+		})
+	}
+}
+
+// a covered row stays visible in the column that hid it, restoring has to cover that column first again
+func TestRoundTripKeepsCoveringOrder(t *testing.T) {
+	for name, encode := range encodings {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+				mat := newTriangleMatrix(t, backend)
+				assert.Nil(t, mat.CoverColumn(1))
+				assert.Nil(t, mat.CoverColumn(0))
+				assertSameMatrix(t, mat, encode(t, mat, backend))
+			})
+		})
+	}
+}
+
+// the columns covered in between the frames have to be replayed in between them, otherwise the frames can't be popped
+func TestRoundTripKeepsInterleavedCovers(t *testing.T) {
+	for name, encode := range encodings {
+		t.Run(name, func(t *testing.T) {
+			forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+				mat := newInterleavingExample(t, backend, false)
+				expected := mat.AsDenseMatrix()
+				assert.Nil(t, mat.CoverColumnByName("G"))
+				assert.Nil(t, mat.Push("P"))
+				assert.Nil(t, mat.CoverColumnByName("X"))
+				assert.Nil(t, mat.Push())
+
+				restored := encode(t, mat, backend)
+				assertSameMatrix(t, mat, restored)
+				assert.Nil(t, restored.Pop())
+				assert.EqualError(t, restored.Pop(),
+					"cannot pop while column at 0 is covered after the last assumption was pushed")
+				assert.Nil(t, restored.UncoverColumnByName("X"))
+				assert.Nil(t, restored.Pop())
+				assert.Nil(t, restored.UncoverColumnByName("G"))
+				assert.Equal(t, expected, restored.AsDenseMatrix())
+			})
+		})
+	}
+}
+
+func TestUnmarshalJSONKeepsOptions(t *testing.T) {
+	data, err := json.Marshal(newDisjointMatrix(t, 2))
+	assert.Nil(t, err)
+	tracer := &recordingTracer{}
+	mat := NewDancingLinkMatrix(WithTracer(tracer), WithDecomposition(DecomposeUpFront))
+	assert.Nil(t, json.Unmarshal(data, mat))
+	assert.Equal(t, 9, mat.Count())
+	assert.NotEmpty(t, tracer.events)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	mat := NewReadMeExample()
+	expected := mat.AsDenseMatrix()
+
+	assert.EqualError(t, mat.UnmarshalJSON([]byte(`{"columns":[{"name":"a"}],"rows":[{"name":"A","columns":[1]}]}`)),
+		"row A references column 1, but there are only 1 columns")
+	assert.EqualError(t, mat.UnmarshalJSON([]byte(`{"columns":[{"name":"a"}],"rows":[],"steps":[{"push":[0]}]}`)),
+		"assumption references row 0, but there are only 0 rows")
+	assert.EqualError(t, mat.UnmarshalJSON([]byte(`{"columns":[],"rows":[],"steps":[{"cover":0}]}`)),
+		"column at index 0 does not exist")
+	assert.EqualError(t, mat.UnmarshalJSON([]byte(`{"columns":[{"name":"a"},{"name":"a"}],"rows":[]}`)),
+		"column a already exists")
+	assert.EqualError(t, mat.UnmarshalJSON([]byte(`{"columns":[],"rows":[{"name":"A"},{"name":"A"}]}`)),
+		"row A already exists")
+	assert.NotNil(t, mat.UnmarshalJSON([]byte(`{"columns":`)))
+
+	data, err := NewReadMeExample().MarshalBinary()
+	assert.Nil(t, err)
+	assert.EqualError(t, mat.UnmarshalBinary([]byte("PBM")), "not a binary encoded matrix")
+	assert.EqualError(t, mat.UnmarshalBinary(append([]byte("DLX"), 2)), "unsupported binary matrix version 2")
+	assert.EqualError(t, mat.UnmarshalBinary(data[:len(data)-3]), "invalid binary matrix: unexpected end of data")
+	assert.EqualError(t, mat.UnmarshalBinary(append(data, 0)), "invalid binary matrix: 1 unexpected trailing bytes")
+
+	// nothing changed on the failed attempts
+	assert.Equal(t, []string{"beer", "nachos", "sour cream"}, mat.Columns())
+	assert.Equal(t, expected, mat.AsDenseMatrix())
+}
+
+func TestBinaryIsCompact(t *testing.T) {
+	mat := newNQueensMatrix(8)
+	jsonData, err := json.Marshal(mat)
+	assert.Nil(t, err)
+	binaryData, err := mat.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Less(t, 3*len(binaryData), len(jsonData))
+}
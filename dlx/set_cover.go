@@ -0,0 +1,254 @@
+package dlx
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MatrixBuilder is the part of the matrices that builds them, code that constructs a DancingLinksMatrixI against it
+// can construct a SetCoverI as well.
+type MatrixBuilder interface {
+	AppendColumn(columnIdentifier string) error
+	AppendRow(rowIdentifier string, rowValues []bool) error
+}
+
+type SetCoverI interface {
+	// Append a new column with the given name. All existing rows are false in the new column.
+	// error is returned when a column with the name already exists.
+	AppendColumn(columnIdentifier string) error
+	// Append a given dense row, error is returned when the number of columns mismatch the registered ones or when a
+	// row with the identifier already exists.
+	AppendRow(rowIdentifier string, rowValues []bool) error
+	// Returns all column identifiers
+	Columns() []string
+	// Returns all row identifiers
+	Rows() []string
+
+	// Returns all minimal covers, which are sets of rows that cover every column at least once and of which no row
+	// can be left out. Unlike exact covers, the rows of a set cover may overlap. If no cover exists, the result is nil.
+	SolveMinimal() [][]string
+	// Returns a cover with the fewest rows, nil if no cover exists.
+	SolveMinimum() []string
+}
+
+// SetCover finds sets of rows that cover every column at least once
+type SetCover struct {
+	columnIdentifiers []string
+	rowIdentifiers    []string
+	rowColumns        [][]int // the column indices of every row
+	identifiers       identifierIndex
+}
+
+func (s *SetCover) AppendColumn(columnIdentifier string) error {
+	if err := s.identifiers.addColumn(columnIdentifier, len(s.columnIdentifiers)); err != nil {
+		return err
+	}
+	s.columnIdentifiers = append(s.columnIdentifiers, columnIdentifier)
+	return nil
+}
+
+func (s *SetCover) AppendRow(rowIdentifier string, rowValues []bool) error {
+	if len(rowValues) != len(s.columnIdentifiers) {
+		return fmt.Errorf("column mismatch: have only %d columns registered, but got %d",
+			len(s.columnIdentifiers), len(rowValues))
+	}
+	if err := s.identifiers.addRow(rowIdentifier, len(s.rowIdentifiers)); err != nil {
+		return err
+	}
+
+	var columns []int
+	for i, v := range rowValues {
+		if v {
+			columns = append(columns, i)
+		}
+	}
+	s.rowIdentifiers = append(s.rowIdentifiers, rowIdentifier)
+	s.rowColumns = append(s.rowColumns, columns)
+	return nil
+}
+
+func (s *SetCover) Columns() []string {
+	return s.columnIdentifiers
+}
+
+func (s *SetCover) Rows() []string {
+	return s.rowIdentifiers
+}
+
+func (s *SetCover) SolveMinimal() [][]string {
+	var result [][]string
+	newSetCoverSearch(s, func(solution []int) {
+		result = append(result, s.rowNames(solution))
+	}).search()
+	return result
+}
+
+func (s *SetCover) SolveMinimum() []string {
+	var best []int
+	found := false
+	search := newSetCoverSearch(s, nil)
+	search.visitor = func(solution []int) {
+		best = append(best[:0], solution...)
+		found = true
+		// from now on only smaller covers are interesting
+		search.bound = len(solution)
+	}
+	search.search()
+	if !found {
+		return nil
+	}
+	return s.rowNames(best)
+}
+
+func (s *SetCover) rowNames(rows []int) []string {
+	sorted := append([]int{}, rows...)
+	sort.Ints(sorted)
+	names := make([]string, len(sorted))
+	for i, r := range sorted {
+		names[i] = s.rowIdentifiers[r]
+	}
+	return names
+}
+
+type setCoverSearch struct {
+	cover      *SetCover
+	columnRows [][]int
+	maxRowSize int
+	visitor    func(solution []int)
+	// only covers with less rows than the bound are searched for, zero means unbounded
+	bound int
+
+	// how many chosen rows cover every column
+	coverCount []int
+	numCovered int
+	// rows are excluded by the branches of their earlier siblings, a row can be excluded several times
+	excluded []int
+	chosen   []int
+}
+
+func newSetCoverSearch(s *SetCover, visitor func(solution []int)) *setCoverSearch {
+	search := &setCoverSearch{
+		cover:      s,
+		columnRows: make([][]int, len(s.columnIdentifiers)),
+		visitor:    visitor,
+		coverCount: make([]int, len(s.columnIdentifiers)),
+		excluded:   make([]int, len(s.rowIdentifiers)),
+	}
+	for r, columns := range s.rowColumns {
+		for _, c := range columns {
+			search.columnRows[c] = append(search.columnRows[c], r)
+		}
+		if len(columns) > search.maxRowSize {
+			search.maxRowSize = len(columns)
+		}
+	}
+	return search
+}
+
+// chooseColumn returns the uncovered column with the fewest rows that can still be chosen together with their
+// number, ties go to the lowest index. Returns -1 when all columns are covered.
+func (s *setCoverSearch) chooseColumn() (int, int) {
+	lowest := -1
+	lowestCount := 0
+	for c, rows := range s.columnRows {
+		if s.coverCount[c] > 0 {
+			continue
+		}
+		count := 0
+		for _, r := range rows {
+			if s.excluded[r] == 0 {
+				count++
+			}
+		}
+		if lowest < 0 || count < lowestCount {
+			lowest = c
+			lowestCount = count
+		}
+	}
+	return lowest, lowestCount
+}
+
+// a row is redundant once all of its columns are covered by other chosen rows as well
+func (s *setCoverSearch) hasRedundantRow() bool {
+	for _, r := range s.chosen {
+		redundant := true
+		for _, c := range s.cover.rowColumns[r] {
+			if s.coverCount[c] == 1 {
+				redundant = false
+				break
+			}
+		}
+		if redundant {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *setCoverSearch) search() {
+	numColumns := len(s.columnRows)
+	if s.numCovered == numColumns {
+		s.visitor(s.chosen)
+		return
+	}
+
+	// every further row covers at most maxRowSize columns
+	if s.bound > 0 && s.maxRowSize > 0 {
+		needed := (numColumns - s.numCovered + s.maxRowSize - 1) / s.maxRowSize
+		if len(s.chosen)+needed >= s.bound {
+			return
+		}
+	}
+
+	column, count := s.chooseColumn()
+	if count == 0 {
+		return
+	}
+
+	// every cover contains exactly one first row of this column, the rows before it are excluded in its branch
+	var tried []int
+	for _, r := range s.columnRows[column] {
+		if s.excluded[r] > 0 {
+			continue
+		}
+		s.choose(r)
+		// adding more rows never makes a redundant row necessary again
+		if !s.hasRedundantRow() {
+			s.search()
+		}
+		s.unchoose(r)
+
+		s.excluded[r]++
+		tried = append(tried, r)
+	}
+	for _, r := range tried {
+		s.excluded[r]--
+	}
+}
+
+func (s *setCoverSearch) choose(rowIndex int) {
+	s.chosen = append(s.chosen, rowIndex)
+	for _, c := range s.cover.rowColumns[rowIndex] {
+		if s.coverCount[c] == 0 {
+			s.numCovered++
+		}
+		s.coverCount[c]++
+	}
+}
+
+func (s *setCoverSearch) unchoose(rowIndex int) {
+	for _, c := range s.cover.rowColumns[rowIndex] {
+		s.coverCount[c]--
+		if s.coverCount[c] == 0 {
+			s.numCovered--
+		}
+	}
+	s.chosen = s.chosen[:len(s.chosen)-1]
+}
+
+func NewSetCover() SetCoverI {
+	return &SetCover{
+		columnIdentifiers: []string{},
+		rowIdentifiers:    []string{},
+	}
+}
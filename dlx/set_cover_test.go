@@ -0,0 +1,133 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+// the same construction works for exact and set covers
+func appendOverlappingRows(t *testing.T, builder MatrixBuilder) {
+	assert.Nil(t, builder.AppendColumn("a"))
+	assert.Nil(t, builder.AppendColumn("b"))
+	assert.Nil(t, builder.AppendColumn("c"))
+	assert.Nil(t, builder.AppendRow("X", []bool{true, true, false}))
+	assert.Nil(t, builder.AppendRow("Y", []bool{false, true, true}))
+	assert.Nil(t, builder.AppendRow("Z", []bool{true, false, true}))
+	assert.Nil(t, builder.AppendRow("W", []bool{true, true, true}))
+}
+
+func TestSetCover(t *testing.T) {
+	cover := NewSetCover()
+	appendOverlappingRows(t, cover)
+	assert.Equal(t, []string{"a", "b", "c"}, cover.Columns())
+	assert.Equal(t, []string{"X", "Y", "Z", "W"}, cover.Rows())
+	assertSameSolutions(t, [][]string{{"W"}, {"X", "Y"}, {"X", "Z"}, {"Y", "Z"}}, cover.SolveMinimal())
+	assert.Equal(t, []string{"W"}, cover.SolveMinimum())
+
+	// the exact cover doesn't allow any overlaps
+	mat := NewDancingLinkMatrix()
+	appendOverlappingRows(t, mat)
+	assert.Equal(t, [][]string{{"W"}}, mat.Solve())
+}
+
+func TestSetCoverWithoutCover(t *testing.T) {
+	cover := NewSetCover()
+	appendOverlappingRows(t, cover)
+	assert.Nil(t, cover.AppendColumn("d"))
+	assert.Nil(t, cover.SolveMinimal())
+	assert.Nil(t, cover.SolveMinimum())
+	assert.EqualError(t, cover.AppendRow("V", []bool{true}), "column mismatch: have only 4 columns registered, but got 1")
+}
+
+func TestSetCoverDuplicateIdentifiers(t *testing.T) {
+	cover := NewSetCover()
+	appendOverlappingRows(t, cover)
+	assert.EqualError(t, cover.AppendColumn("a"), "column a already exists")
+	assert.EqualError(t, cover.AppendRow("X", []bool{false, false, true}), "row X already exists")
+	assert.Equal(t, []string{"a", "b", "c"}, cover.Columns())
+	assert.Equal(t, []string{"X", "Y", "Z", "W"}, cover.Rows())
+	assertSameSolutions(t, [][]string{{"W"}, {"X", "Y"}, {"X", "Z"}, {"Y", "Z"}}, cover.SolveMinimal())
+}
+
+func TestSetCoverWithoutColumns(t *testing.T) {
+	cover := NewSetCover()
+	assert.Equal(t, [][]string{{}}, cover.SolveMinimal())
+	assert.Equal(t, []string{}, cover.SolveMinimum())
+}
+
+func TestSetCoverAgreesWithBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		numColumns := 1 + random.Intn(7)
+		numRows := 1 + random.Intn(10)
+		cover := NewSetCover()
+		for c := 0; c < numColumns; c++ {
+			assert.Nil(t, cover.AppendColumn(fmt.Sprintf("%d", c)))
+		}
+		dense := make([][]bool, numRows)
+		for r := range dense {
+			dense[r] = make([]bool, numColumns)
+			for c := range dense[r] {
+				dense[r][c] = random.Float64() < 0.35
+			}
+			assert.Nil(t, cover.AppendRow(fmt.Sprintf("r%d", r), dense[r]))
+		}
+
+		expected, minimum := bruteForceMinimalCovers(dense)
+		assertSameSolutions(t, expected, cover.SolveMinimal())
+		if expected == nil {
+			assert.Nil(t, cover.SolveMinimum())
+		} else {
+			assert.Equal(t, minimum, len(cover.SolveMinimum()), "matrix %v", dense)
+		}
+	}
+}
+
+// tries all subsets of rows and keeps the covers that don't have a redundant row
+func bruteForceMinimalCovers(dense [][]bool) ([][]string, int) {
+	var result [][]string
+	minimum := len(dense) + 1
+	for subset := 0; subset < 1<<uint(len(dense)); subset++ {
+		counts := make([]int, len(dense[0]))
+		var rows []int
+		for r, row := range dense {
+			if subset&(1<<uint(r)) == 0 {
+				continue
+			}
+			rows = append(rows, r)
+			for c, v := range row {
+				if v {
+					counts[c]++
+				}
+			}
+		}
+
+		covered := true
+		for _, count := range counts {
+			covered = covered && count > 0
+		}
+		redundant := false
+		for _, r := range rows {
+			necessary := false
+			for c, v := range dense[r] {
+				necessary = necessary || (v && counts[c] == 1)
+			}
+			redundant = redundant || !necessary
+		}
+		if !covered || redundant {
+			continue
+		}
+
+		names := []string{}
+		for _, r := range rows {
+			names = append(names, fmt.Sprintf("r%d", r))
+		}
+		result = append(result, names)
+		if len(rows) < minimum {
+			minimum = len(rows)
+		}
+	}
+	return result, minimum
+}
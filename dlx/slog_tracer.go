@@ -0,0 +1,40 @@
+//go:build go1.21
+// +build go1.21
+
+package dlx
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogTracer writes every event of the search as a structured log record
+type SlogTracer struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+func (t *SlogTracer) OnChooseColumn(column string, size int) {
+	t.log("choose column", slog.String("column", column), slog.Int("size", size))
+}
+
+func (t *SlogTracer) OnTryRow(row string, depth int) {
+	t.log("try row", slog.String("row", row), slog.Int("depth", depth))
+}
+
+func (t *SlogTracer) OnBacktrack(depth int) {
+	t.log("backtrack", slog.Int("depth", depth))
+}
+
+func (t *SlogTracer) OnSolution(rows []string) {
+	t.log("solution", slog.Any("rows", rows))
+}
+
+func (t *SlogTracer) log(msg string, attrs ...slog.Attr) {
+	t.logger.LogAttrs(context.Background(), t.level, msg, attrs...)
+}
+
+// NewSlogTracer creates a tracer that logs all events on the given level, usually slog.LevelDebug
+func NewSlogTracer(logger *slog.Logger, level slog.Level) Tracer {
+	return &SlogTracer{logger: logger, level: level}
+}
@@ -0,0 +1,41 @@
+//go:build go1.21
+// +build go1.21
+
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogTracer(t *testing.T) {
+	sb := &strings.Builder{}
+	handler := slog.NewTextHandler(sb, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		// leave out the time to make the output stable
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	mat := NewReadMeExample(WithTracer(NewSlogTracer(slog.New(handler), slog.LevelDebug)))
+	mat.SolveOne()
+	assert.Equal(t, `level=DEBUG msg="choose column" column=nachos size=2
+level=DEBUG msg="try row" row=Amanda depth=0
+level=DEBUG msg="choose column" column="sour cream" size=1
+level=DEBUG msg="try row" row=Chris depth=1
+level=DEBUG msg=solution rows="[Amanda Chris]"
+level=DEBUG msg=backtrack depth=1
+level=DEBUG msg=backtrack depth=0
+`, sb.String())
+
+	// nothing is written below the level of the handler
+	sb.Reset()
+	mat = NewReadMeExample(WithTracer(NewSlogTracer(slog.New(handler), slog.LevelDebug-1)))
+	mat.SolveOne()
+	assert.Equal(t, "", sb.String())
+}
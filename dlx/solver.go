@@ -0,0 +1,351 @@
+package dlx
+
+import "sort"
+
+// coverBackend contains the primitives that every matrix representation implements,
+// the search and the assumptions are shared between all of them on top of these.
+type coverBackend interface {
+	Columns() []string
+	Rows() []string
+	ColumnIndex(columnIdentifier string) (int, bool)
+	RowIndex(rowIdentifier string) (int, bool)
+
+	// returns the uncovered primary column with the fewest available rows, ties go to the lowest index.
+	// Returns -1 when all primary columns are covered.
+	chooseColumn() int
+	// appends the indices of the rows that are currently available in the given column
+	appendRows(rows []int, columnIndex int) []int
+	// returns the indices of all columns the given row is true in
+	rowColumns(rowIndex int) []int
+	isPrimary(columnIndex int) bool
+	isCovered(columnIndex int) bool
+
+	// covers and uncovers without any validation, callers need to guarantee the reverse order when uncovering
+	cover(columnIndex int)
+	uncover(columnIndex int)
+	// covers all other columns of a row that was chosen to cover the given (already covered) column
+	selectRow(rowIndex, columnIndex int)
+	// undoes selectRow
+	deselectRow(rowIndex, columnIndex int)
+}
+
+// searchConfig holds the settings that every search of a matrix runs with
+type searchConfig struct {
+	trace         *SearchTrace
+	tracer        Tracer
+	orderer       RowOrderer
+	decomposition Decomposition
+}
+
+// searcher runs Algorithm X iteratively on an explicit stack of levels, so the search can be suspended after every
+// solution and all its state is reused between the nodes instead of being allocated on the Go stack.
+type searcher struct {
+	backend coverBackend
+	trace   *SearchTrace
+	tracer  Tracer
+	columns []string
+	rows    []string
+	visitor func(solution []int) bool
+	// the levels of the search, the first depth of them are open. The levels are reused to avoid allocating them
+	// on every node.
+	levels []searchLevel
+	depth  int
+	// the forced rows followed by the chosen row of every open level
+	partialSolution []int
+	// whether the next step opens a new level, otherwise it advances the deepest open level
+	descend bool
+	// the index of the tried candidate on every level of the search
+	path []int
+	// the path of a solution to resume the search after, the search skips everything up to and including it
+	resume []int
+	// tries the rows in the order of their indices, some backends reorder the rows of a column while searching
+	sorted bool
+	// tries the rows in the order of their ranks, nil without a RowOrderer
+	ranks *rowRanks
+	// the rows that are never tried, nil if all rows can be tried
+	excluded []bool
+	// the number of rows tried so far and the most rows that may be tried, zero tries all of them
+	nodes    int
+	maxNodes int
+	// whether the search was cut off at maxNodes
+	nodeLimitHit bool
+}
+
+// searchLevel is the chosen column of a level together with its rows
+type searchLevel struct {
+	column int
+	// the available rows of the column when it was chosen
+	candidates []int
+	// the index of the next candidate to try
+	next int
+	// the currently chosen row, -1 before the first and after the last candidate
+	row int
+}
+
+// search hands every solution it finds to the visitor, the solution slice is only valid during the call.
+// The search stops as soon as the visitor returns false, which is also what search returns in that case.
+func search(backend coverBackend, trace *SearchTrace, tracer Tracer, orderer RowOrderer, partialSolution []int,
+	visitor func(solution []int) bool) bool {
+	return newSearcher(backend, trace, tracer, orderer, visitor).search(partialSolution)
+}
+
+func newSearcher(backend coverBackend, trace *SearchTrace, tracer Tracer, orderer RowOrderer,
+	visitor func(solution []int) bool) *searcher {
+	return &searcher{
+		backend: backend,
+		trace:   trace,
+		tracer:  tracer,
+		columns: backend.Columns(),
+		rows:    backend.Rows(),
+		visitor: visitor,
+		ranks:   newRowRanks(backend, orderer),
+	}
+}
+
+func (s *searcher) search(partialSolution []int) bool {
+	s.start(partialSolution)
+	for s.next() {
+		if !s.visitor(s.partialSolution) {
+			s.stop()
+			return false
+		}
+	}
+	return true
+}
+
+// start prepares a new search below the given forced rows
+func (s *searcher) start(partialSolution []int) {
+	s.partialSolution = partialSolution
+	s.depth = 0
+	s.descend = true
+	s.nodes = 0
+	s.nodeLimitHit = false
+}
+
+// next runs the search up to the next solution and returns true, the solution is s.partialSolution until next is
+// called again. Returns false once the search is exhausted, the matrix is restored by then.
+func (s *searcher) next() bool {
+	for {
+		if s.descend {
+			s.descend = false
+			if !s.open() {
+				if s.resume != nil {
+					// this is the solution to resume after, everything from here on is new
+					s.resume = nil
+					continue
+				}
+				s.trace.solution()
+				if s.tracer != nil {
+					s.tracer.OnSolution(mapRowNames(s.rows, [][]int{s.partialSolution})[0])
+				}
+				return true
+			}
+		}
+		if s.depth == 0 {
+			return false
+		}
+		s.descend = s.advance()
+	}
+}
+
+// stop closes all open levels, which restores the matrix
+func (s *searcher) stop() {
+	for s.depth > 0 {
+		level := &s.levels[s.depth-1]
+		level.next = len(level.candidates)
+		s.advance()
+	}
+	s.descend = false
+}
+
+// open chooses the next column and opens a level for it. Returns false if all columns are covered and true
+// otherwise, also when the column has no rows: such a dead end is not even covered and the search backtracks.
+func (s *searcher) open() bool {
+	column := s.backend.chooseColumn()
+	if column < 0 {
+		return false
+	}
+
+	// covering the column only hides its rows from the other columns, so they can be collected before
+	if s.depth == len(s.levels) {
+		s.levels = append(s.levels, searchLevel{})
+	}
+	level := &s.levels[s.depth]
+	level.candidates = s.backend.appendRows(level.candidates[:0], column)
+	if s.tracer != nil {
+		s.tracer.OnChooseColumn(s.columns[column], len(level.candidates))
+	}
+	if len(level.candidates) == 0 {
+		if s.trace != nil {
+			s.trace.deadEnd(s.columns[column])
+		}
+		return true
+	}
+
+	s.backend.cover(column)
+	if s.ranks != nil {
+		s.ranks.sort(level.candidates, column)
+	} else if s.sorted {
+		sort.Ints(level.candidates)
+	}
+	level.column = column
+	level.next = 0
+	if s.depth < len(s.resume) {
+		level.next = s.resume[s.depth]
+	}
+	level.row = -1
+	s.depth++
+	return true
+}
+
+// advance reverts the chosen row of the deepest level and chooses its next candidate, which returns true. Without
+// another candidate the level is closed and its column uncovered again.
+func (s *searcher) advance() bool {
+	depth := s.depth - 1
+	level := &s.levels[depth]
+	if level.row >= 0 {
+		// revert the last covering for the next row iteration
+		s.backend.deselectRow(level.row, level.column)
+		if s.trace != nil {
+			s.trace.leave()
+		}
+		if s.tracer != nil {
+			s.tracer.OnBacktrack(depth)
+		}
+		s.partialSolution = s.partialSolution[:len(s.partialSolution)-1]
+		level.row = -1
+	}
+
+	for level.next < len(level.candidates) {
+		if s.maxNodes > 0 && s.nodes == s.maxNodes {
+			// out of nodes, every level is closed like an exhausted one
+			s.nodeLimitHit = true
+			break
+		}
+		i := level.next
+		level.next++
+		row := level.candidates[i]
+		if s.excluded != nil && s.excluded[row] {
+			continue
+		}
+
+		// we're adding the next eligible row to the solution, all other columns of it need to be covered too
+		s.path = append(s.path[:depth], i)
+		s.partialSolution = append(s.partialSolution, row)
+		if s.trace != nil {
+			s.trace.enter(s.columns[level.column], s.rows[row])
+		}
+		if s.tracer != nil {
+			s.tracer.OnTryRow(s.rows[row], depth)
+		}
+		s.backend.selectRow(row, level.column)
+		level.row = row
+		s.nodes++
+		return true
+	}
+
+	s.backend.uncover(level.column)
+	s.depth--
+	return false
+}
+
+// rowRanks sorts the rows of a column by their ranks from a RowOrderer and by their indices within the same rank,
+// the slices are reused between the columns
+type rowRanks struct {
+	orderer    RowOrderer
+	columns    []string
+	rows       []string
+	candidates []int
+	ranks      []int
+}
+
+func newRowRanks(backend coverBackend, orderer RowOrderer) *rowRanks {
+	if orderer == nil {
+		return nil
+	}
+	return &rowRanks{orderer: orderer, columns: backend.Columns(), rows: backend.Rows()}
+}
+
+func (r *rowRanks) sort(candidates []int, column int) {
+	r.candidates = candidates
+	r.ranks = r.ranks[:0]
+	for _, row := range candidates {
+		r.ranks = append(r.ranks, r.orderer(r.columns[column], r.rows[row]))
+	}
+	sort.Sort(r)
+	r.candidates = nil
+}
+
+func (r *rowRanks) Len() int {
+	return len(r.candidates)
+}
+
+func (r *rowRanks) Less(i, j int) bool {
+	if r.ranks[i] != r.ranks[j] {
+		return r.ranks[i] > r.ranks[j]
+	}
+	return r.candidates[i] < r.candidates[j]
+}
+
+func (r *rowRanks) Swap(i, j int) {
+	r.candidates[i], r.candidates[j] = r.candidates[j], r.candidates[i]
+	r.ranks[i], r.ranks[j] = r.ranks[j], r.ranks[i]
+}
+
+// run searches with or without decomposition, the decomposed search collects at most limit solutions per component
+// if limit is positive.
+func run(backend coverBackend, config *searchConfig, partialSolution []int, limit int, visitor func(solution []int) bool) bool {
+	if config.decomposition == NoDecomposition {
+		return search(backend, config.trace, config.tracer, config.orderer, partialSolution, visitor)
+	}
+	d := &decomposer{
+		backend:      backend,
+		tracer:       config.tracer,
+		orderer:      config.orderer,
+		duringSearch: config.decomposition == DecomposeDuringSearch,
+		limit:        limit,
+	}
+	return d.enumerate(partialSolution, visitor)
+}
+
+func solveAll(backend coverBackend, config *searchConfig, a *assumptions) [][]string {
+	return solve(backend, config, a, SolveOptions{}).Solutions
+}
+
+func solveOne(backend coverBackend, config *searchConfig, a *assumptions) []string {
+	result := solve(backend, config, a, SolveOptions{MaxSolutions: 1})
+	if len(result.Solutions) == 0 {
+		return nil
+	}
+	return result.Solutions[0]
+}
+
+func count(backend coverBackend, config *searchConfig, a *assumptions) int {
+	config.trace.reset()
+	if config.decomposition != NoDecomposition {
+		d := &decomposer{
+			backend:      backend,
+			tracer:       config.tracer,
+			duringSearch: config.decomposition == DecomposeDuringSearch,
+		}
+		return d.count()
+	}
+
+	n := 0
+	search(backend, config.trace, config.tracer, nil, a.newPartialSolution(), func(solution []int) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+func mapRowNames(rowIdentifiers []string, searchResult [][]int) [][]string {
+	c := make([][]string, len(searchResult))
+	for i, row := range searchResult {
+		c[i] = make([]string, len(row))
+		for ji, j := range row {
+			c[i][ji] = rowIdentifiers[j]
+		}
+	}
+	return c
+}
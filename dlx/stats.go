@@ -0,0 +1,131 @@
+package dlx
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MatrixStats describes the structure of a matrix, independent of which columns are currently covered
+type MatrixStats struct {
+	NumRows             int
+	NumColumns          int
+	NumPrimaryColumns   int
+	NumSecondaryColumns int
+	// the number of true cells
+	NumNodes int
+	// the share of true cells, zero for a matrix without cells
+	Density float64
+
+	// the fewest and the most rows of any column, zero without columns
+	MinColumnSize int
+	MaxColumnSize int
+	// the number of columns with the given number of rows, indexed by that number up to MaxColumnSize
+	ColumnSizeHistogram []int
+
+	// primary columns without any row, they can never be covered and the matrix has no solution
+	EmptyPrimaryColumns []string
+	// groups of rows that are true in exactly the same columns, every solution with one of them has twins
+	DuplicateRows [][]string
+	// rows that aren't true in any column, the search never chooses them
+	EmptyRows []string
+}
+
+func stats(backend coverBackend) *MatrixStats {
+	columns := backend.Columns()
+	rows := backend.Rows()
+	s := &MatrixStats{NumRows: len(rows), NumColumns: len(columns)}
+
+	columnSizes := make([]int, len(columns))
+	rowsByColumns := map[string]int{}
+	for r, row := range rows {
+		rowColumns := backend.rowColumns(r)
+		if len(rowColumns) == 0 {
+			s.EmptyRows = append(s.EmptyRows, row)
+			continue
+		}
+
+		s.NumNodes += len(rowColumns)
+		indices := make([]string, len(rowColumns))
+		for i, c := range rowColumns {
+			columnSizes[c]++
+			indices[i] = strconv.Itoa(c)
+		}
+		// the first row with these columns starts a group, its twins are added to it
+		key := strings.Join(indices, ",")
+		group, ok := rowsByColumns[key]
+		if !ok {
+			rowsByColumns[key] = len(s.DuplicateRows)
+			s.DuplicateRows = append(s.DuplicateRows, []string{row})
+		} else {
+			s.DuplicateRows[group] = append(s.DuplicateRows[group], row)
+		}
+	}
+
+	var duplicates [][]string
+	for _, group := range s.DuplicateRows {
+		if len(group) > 1 {
+			duplicates = append(duplicates, group)
+		}
+	}
+	s.DuplicateRows = duplicates
+
+	if s.NumRows > 0 && s.NumColumns > 0 {
+		s.Density = float64(s.NumNodes) / float64(s.NumRows*s.NumColumns)
+	}
+
+	for c, size := range columnSizes {
+		if backend.isPrimary(c) {
+			s.NumPrimaryColumns++
+			if size == 0 {
+				s.EmptyPrimaryColumns = append(s.EmptyPrimaryColumns, columns[c])
+			}
+		} else {
+			s.NumSecondaryColumns++
+		}
+
+		if c == 0 || size < s.MinColumnSize {
+			s.MinColumnSize = size
+		}
+		if size > s.MaxColumnSize {
+			s.MaxColumnSize = size
+		}
+	}
+	if len(columns) > 0 {
+		s.ColumnSizeHistogram = make([]int, s.MaxColumnSize+1)
+		for _, size := range columnSizes {
+			s.ColumnSizeHistogram[size]++
+		}
+	}
+	return s
+}
+
+// WriteReport writes the statistics in a human readable form and lists the problems of the encoding last
+func (s *MatrixStats) WriteReport(writer io.StringWriter) error {
+	sb := &strings.Builder{}
+	sb.WriteString(fmt.Sprintf("rows: %d\n", s.NumRows))
+	sb.WriteString(fmt.Sprintf("columns: %d (%d primary, %d secondary)\n",
+		s.NumColumns, s.NumPrimaryColumns, s.NumSecondaryColumns))
+	sb.WriteString(fmt.Sprintf("nodes: %d (density %.4f)\n", s.NumNodes, s.Density))
+	sb.WriteString(fmt.Sprintf("rows per column: min %d, max %d\n", s.MinColumnSize, s.MaxColumnSize))
+	for size, n := range s.ColumnSizeHistogram {
+		if n > 0 {
+			sb.WriteString(fmt.Sprintf("  %d rows: %d columns\n", size, n))
+		}
+	}
+
+	if len(s.EmptyPrimaryColumns) > 0 {
+		sb.WriteString(fmt.Sprintf("primary columns without rows, there is no solution: %s\n",
+			strings.Join(s.EmptyPrimaryColumns, ", ")))
+	}
+	for _, group := range s.DuplicateRows {
+		sb.WriteString(fmt.Sprintf("duplicate rows: %s\n", strings.Join(group, ", ")))
+	}
+	if len(s.EmptyRows) > 0 {
+		sb.WriteString(fmt.Sprintf("rows without columns: %s\n", strings.Join(s.EmptyRows, ", ")))
+	}
+
+	_, err := writer.WriteString(sb.String())
+	return err
+}
@@ -0,0 +1,71 @@
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Equal(t, &MatrixStats{
+			NumRows:             4,
+			NumColumns:          3,
+			NumPrimaryColumns:   3,
+			NumNodes:            7,
+			Density:             7.0 / 12.0,
+			MinColumnSize:       2,
+			MaxColumnSize:       3,
+			ColumnSizeHistogram: []int{0, 0, 2, 1},
+		}, mat.Stats())
+	})
+}
+
+func TestStatsFindsEncodingProblems(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendColumn("chips"))
+		assert.Nil(t, mat.AppendSecondaryColumn("cups"))
+		assert.Nil(t, mat.AppendRow("Nobody", []bool{false, false, false, false, false}))
+		assert.Nil(t, mat.AppendRow("Jake", []bool{true, true, false, false, false}))
+		assert.Nil(t, mat.AppendRow("Jacky", []bool{true, false, false, false, false}))
+		assert.Nil(t, mat.AppendRow("Ann", []bool{true, true, false, false, false}))
+
+		// covering doesn't change the structure
+		assert.Nil(t, mat.CoverColumn(0))
+		s := mat.Stats()
+		assert.Nil(t, mat.UncoverColumn(0))
+
+		assert.Equal(t, 8, s.NumRows)
+		assert.Equal(t, 5, s.NumColumns)
+		assert.Equal(t, 4, s.NumPrimaryColumns)
+		assert.Equal(t, 1, s.NumSecondaryColumns)
+		assert.Equal(t, 12, s.NumNodes)
+		assert.Equal(t, 0, s.MinColumnSize)
+		assert.Equal(t, 6, s.MaxColumnSize)
+		assert.Equal(t, []int{2, 0, 1, 0, 1, 0, 1}, s.ColumnSizeHistogram)
+		assert.Equal(t, []string{"chips"}, s.EmptyPrimaryColumns)
+		assert.Equal(t, [][]string{{"Jack", "Jacky"}, {"Amanda", "Jake", "Ann"}}, s.DuplicateRows)
+		assert.Equal(t, []string{"Nobody"}, s.EmptyRows)
+
+		sb := &strings.Builder{}
+		assert.Nil(t, s.WriteReport(sb))
+		assert.Equal(t, "rows: 8\n"+
+			"columns: 5 (4 primary, 1 secondary)\n"+
+			"nodes: 12 (density 0.3000)\n"+
+			"rows per column: min 0, max 6\n"+
+			"  0 rows: 2 columns\n"+
+			"  2 rows: 1 columns\n"+
+			"  4 rows: 1 columns\n"+
+			"  6 rows: 1 columns\n"+
+			"primary columns without rows, there is no solution: chips\n"+
+			"duplicate rows: Jack, Jacky\n"+
+			"duplicate rows: Amanda, Jake, Ann\n"+
+			"rows without columns: Nobody\n", sb.String())
+	})
+}
+
+func TestStatsEmptyMatrix(t *testing.T) {
+	assert.Equal(t, &MatrixStats{}, NewDancingLinkMatrix().Stats())
+}
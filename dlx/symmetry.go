@@ -0,0 +1,159 @@
+package dlx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Symmetry maps the matrix onto itself: row i becomes row Rows[i] and column j becomes column Columns[j].
+// Every row has to be mapped onto the row whose columns are the images of its own columns.
+type Symmetry struct {
+	Rows    []int
+	Columns []int
+}
+
+// SymmetricSolution is the canonical representative of a class of solutions that are images of each other
+type SymmetricSolution struct {
+	// the identifiers of the rows of the canonical solution
+	Rows []string
+	// the number of distinct solutions in the class, including this one
+	OrbitSize int
+}
+
+func validateSymmetries(backend coverBackend, symmetries []Symmetry) error {
+	numRows := len(backend.Rows())
+	numColumns := len(backend.Columns())
+	for i, s := range symmetries {
+		if len(s.Rows) != numRows || len(s.Columns) != numColumns {
+			return fmt.Errorf("symmetry %d maps %d rows and %d columns, but the matrix has %d rows and %d columns",
+				i, len(s.Rows), len(s.Columns), numRows, numColumns)
+		}
+		if !isPermutation(s.Rows) || !isPermutation(s.Columns) {
+			return fmt.Errorf("symmetry %d is not a permutation", i)
+		}
+		for c, image := range s.Columns {
+			if backend.isPrimary(c) != backend.isPrimary(image) {
+				return fmt.Errorf("symmetry %d maps column %s onto %s, which is not of the same kind",
+					i, backend.Columns()[c], backend.Columns()[image])
+			}
+		}
+		for r, image := range s.Rows {
+			var mapped []int
+			for _, c := range backend.rowColumns(r) {
+				mapped = append(mapped, s.Columns[c])
+			}
+			if !sameColumns(mapped, backend.rowColumns(image)) {
+				return fmt.Errorf("symmetry %d maps row %s onto %s, but their columns don't match",
+					i, backend.Rows()[r], backend.Rows()[image])
+			}
+		}
+	}
+	return nil
+}
+
+func isPermutation(p []int) bool {
+	seen := make([]bool, len(p))
+	for _, i := range p {
+		if i < 0 || i >= len(p) || seen[i] {
+			return false
+		}
+		seen[i] = true
+	}
+	return true
+}
+
+func sameColumns(a []int, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]int{}, a...)
+	b = append([]int{}, b...)
+	sort.Ints(a)
+	sort.Ints(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// orbit applies the symmetries until no new solutions show up. Returns whether the solution is the canonical one,
+// which is the lexicographically smallest of the sorted row indices in the orbit, and the size of the orbit.
+func orbit(solution []int, symmetries []Symmetry) (bool, int) {
+	start := append([]int{}, solution...)
+	sort.Ints(start)
+	startKey := solutionKey(start)
+
+	seen := map[string]bool{startKey: true}
+	queue := [][]int{start}
+	canonical := true
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, s := range symmetries {
+			image := make([]int, len(current))
+			for i, r := range current {
+				image[i] = s.Rows[r]
+			}
+			sort.Ints(image)
+			key := solutionKey(image)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			queue = append(queue, image)
+			if lessSolution(image, start) {
+				canonical = false
+			}
+		}
+	}
+	return canonical, len(seen)
+}
+
+func solutionKey(sortedRows []int) string {
+	sb := strings.Builder{}
+	for _, r := range sortedRows {
+		sb.WriteString(strconv.Itoa(r))
+		sb.WriteString(",")
+	}
+	return sb.String()
+}
+
+func lessSolution(a []int, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// solveUpToSymmetry filters the solutions of the search down to the canonical representatives of their orbits.
+// The symmetries map the whole matrix, the forced rows of the assumptions would need to be fixed by all of them.
+func solveUpToSymmetry(backend coverBackend, config *searchConfig, a *assumptions, symmetries []Symmetry) ([]SymmetricSolution, error) {
+	if a.size() > 0 {
+		return nil, fmt.Errorf("cannot solve up to symmetry while %d assumptions are pushed", a.size())
+	}
+	if err := validateSymmetries(backend, symmetries); err != nil {
+		return nil, err
+	}
+
+	config.trace.reset()
+	rows := backend.Rows()
+	var result []SymmetricSolution
+	run(backend, config, a.newPartialSolution(), 0, func(solution []int) bool {
+		canonical, orbitSize := orbit(solution, symmetries)
+		if canonical {
+			names := make([]string, len(solution))
+			for i, r := range solution {
+				names[i] = rows[r]
+			}
+			result = append(result, SymmetricSolution{Rows: names, OrbitSize: orbitSize})
+		}
+		return true
+	})
+	return result, nil
+}
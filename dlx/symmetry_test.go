@@ -0,0 +1,101 @@
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+// swaps the two copies of the disjoint matrix
+func newSwapSymmetry() Symmetry {
+	s := Symmetry{Rows: make([]int, 10), Columns: make([]int, 6)}
+	for i := range s.Rows {
+		s.Rows[i] = (i + 5) % 10
+	}
+	for i := range s.Columns {
+		s.Columns[i] = (i + 3) % 6
+	}
+	return s
+}
+
+func TestSolveUpToSymmetry(t *testing.T) {
+	for _, decomposition := range []Decomposition{NoDecomposition, DecomposeUpFront} {
+		forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+			mat := newDisjointMatrix(t, 2, backend, WithDecomposition(decomposition))
+			result, err := mat.SolveUpToSymmetry([]Symmetry{newSwapSymmetry()})
+			assert.Nil(t, err)
+
+			var canonical [][]string
+			orbitSizes := map[string]int{}
+			total := 0
+			for _, s := range result {
+				canonical = append(canonical, s.Rows)
+				orbitSizes[normalizeSolutions([][]string{s.Rows})[0]] = s.OrbitSize
+				total += s.OrbitSize
+			}
+			assertSameSolutions(t, [][]string{
+				{"0_A", "1_A"}, {"0_A", "1_B", "1_C"}, {"0_A", "1_D", "1_E"},
+				{"0_B", "0_C", "1_B", "1_C"}, {"0_B", "0_C", "1_D", "1_E"},
+				{"0_D", "0_E", "1_D", "1_E"},
+			}, canonical)
+			assert.Equal(t, 1, orbitSizes["0_A,1_A"])
+			assert.Equal(t, 2, orbitSizes["0_A,1_B,1_C"])
+			assert.Equal(t, mat.Count(), total)
+		})
+	}
+}
+
+func TestSolveUpToSymmetryWithoutSymmetries(t *testing.T) {
+	mat := NewReadMeExample()
+	result, err := mat.SolveUpToSymmetry(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, []SymmetricSolution{
+		{Rows: []string{"Amanda", "Chris"}, OrbitSize: 1},
+		{Rows: []string{"Jen"}, OrbitSize: 1},
+	}, result)
+}
+
+func TestSolveUpToSymmetryWithAssumptions(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := newDisjointMatrix(t, 2, backend)
+		// the swap maps the forced row 1_A onto 0_A, so the classes would mix solutions with and without it
+		assert.Nil(t, mat.Push("1_A"))
+		_, err := mat.SolveUpToSymmetry([]Symmetry{newSwapSymmetry()})
+		assert.EqualError(t, err, "cannot solve up to symmetry while 1 assumptions are pushed")
+
+		assert.Nil(t, mat.Pop())
+		result, err := mat.SolveUpToSymmetry([]Symmetry{newSwapSymmetry()})
+		assert.Nil(t, err)
+		assert.Len(t, result, 6)
+	})
+}
+
+func TestSolveUpToSymmetryErrors(t *testing.T) {
+	mat := newDisjointMatrix(t, 2)
+	identity := func(n int) []int {
+		p := make([]int, n)
+		for i := range p {
+			p[i] = i
+		}
+		return p
+	}
+
+	_, err := mat.SolveUpToSymmetry([]Symmetry{{Rows: identity(10), Columns: identity(5)}})
+	assert.EqualError(t, err, "symmetry 0 maps 10 rows and 5 columns, but the matrix has 10 rows and 6 columns")
+
+	notAPermutation := identity(10)
+	notAPermutation[0] = 1
+	_, err = mat.SolveUpToSymmetry([]Symmetry{{Rows: notAPermutation, Columns: identity(6)}})
+	assert.EqualError(t, err, "symmetry 0 is not a permutation")
+
+	// the rows are swapped, but the columns stay
+	swap := newSwapSymmetry()
+	_, err = mat.SolveUpToSymmetry([]Symmetry{swap, {Rows: swap.Rows, Columns: identity(6)}})
+	assert.EqualError(t, err, "symmetry 1 maps row 0_A onto 1_A, but their columns don't match")
+
+	assert.Nil(t, mat.AppendSecondaryColumn("x"))
+	assert.Nil(t, mat.AppendSecondaryColumn("y"))
+	columns := identity(8)
+	columns[0], columns[6] = 6, 0
+	_, err = mat.SolveUpToSymmetry([]Symmetry{{Rows: identity(10), Columns: columns}})
+	assert.EqualError(t, err, "symmetry 0 maps column 0_0 onto x, which is not of the same kind")
+}
@@ -0,0 +1,14 @@
+package dlx
+
+// Tracer receives the events of the search, for example to log them or to collect metrics. The search calls it
+// synchronously, so it should return quickly. Without a tracer the search only pays for a nil check per event.
+type Tracer interface {
+	// called when the search chose the column with the fewest rows, size is the number of rows it can try in it
+	OnChooseColumn(column string, size int)
+	// called before the row is added to the partial solution on the given depth, the first row has depth zero
+	OnTryRow(row string, depth int)
+	// called after the row that was tried on the given depth was removed from the partial solution again
+	OnBacktrack(depth int)
+	// called for every solution before it is handed out, the rows are only valid during the call
+	OnSolution(rows []string)
+}
@@ -0,0 +1,77 @@
+package dlx
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type recordingTracer struct {
+	events []string
+}
+
+func (r *recordingTracer) OnChooseColumn(column string, size int) {
+	r.events = append(r.events, fmt.Sprintf("choose %s %d", column, size))
+}
+
+func (r *recordingTracer) OnTryRow(row string, depth int) {
+	r.events = append(r.events, fmt.Sprintf("try %s %d", row, depth))
+}
+
+func (r *recordingTracer) OnBacktrack(depth int) {
+	r.events = append(r.events, fmt.Sprintf("backtrack %d", depth))
+}
+
+func (r *recordingTracer) OnSolution(rows []string) {
+	r.events = append(r.events, fmt.Sprintf("solution %v", rows))
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &recordingTracer{}
+	mat := NewReadMeExample(WithTracer(tracer))
+	assert.Equal(t, 2, len(mat.Solve()))
+	assert.Equal(t, []string{
+		"choose nachos 2",
+		"try Amanda 0",
+		"choose sour cream 1",
+		"try Chris 1",
+		"solution [Amanda Chris]",
+		"backtrack 1",
+		"backtrack 0",
+		"try Jen 0",
+		"solution [Jen]",
+		"backtrack 0",
+	}, tracer.events)
+}
+
+func TestTracerDeadEnd(t *testing.T) {
+	tracer := &recordingTracer{}
+	mat := NewReadMeExample(WithTracer(tracer))
+	assert.Nil(t, mat.AppendColumn("chips"))
+	assert.Nil(t, mat.SolveOne())
+	assert.Equal(t, []string{"choose chips 0"}, tracer.events)
+}
+
+func TestTracerMatchesSearchTrace(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		tracer := &recordingTracer{}
+		mat := newNQueensMatrix(5, backend, WithTracer(tracer))
+		trace := mat.TraceSearch(100000)
+		assert.Equal(t, 10, mat.Count())
+
+		counts := map[string]int{}
+		for _, event := range tracer.events {
+			var kind string
+			_, _ = fmt.Sscan(event, &kind)
+			counts[kind]++
+			if strings.HasPrefix(event, "choose") && strings.HasSuffix(event, " 0") {
+				counts["dead end"]++
+			}
+		}
+		// the trace has an additional root node and a node for every dead end
+		assert.Equal(t, trace.NumNodes()-1, counts["try"]+counts["dead end"])
+		assert.Equal(t, counts["try"], counts["backtrack"])
+		assert.Equal(t, 10, counts["solution"])
+	})
+}
@@ -0,0 +1,63 @@
+package dlx
+
+// Uniqueness tells whether a matrix has no, exactly one or more solutions
+type Uniqueness int
+
+const (
+	UniquenessNone Uniqueness = iota
+	UniquenessUnique
+	UniquenessMultiple
+)
+
+func (u Uniqueness) String() string {
+	switch u {
+	case UniquenessNone:
+		return "None"
+	case UniquenessUnique:
+		return "Unique"
+	case UniquenessMultiple:
+		return "Multiple"
+	default:
+		return "Unknown"
+	}
+}
+
+// UniquenessCheck is the result of CheckUnique
+type UniquenessCheck struct {
+	Uniqueness Uniqueness
+	// no solution, the only one or the first two solutions in the order of the search
+	Witnesses [][]string
+	// the column that the search branched on when the two witnesses chose different rows for it, so each witness
+	// covers it with another row. Empty unless there are multiple solutions.
+	DivergingColumn string
+}
+
+// checkUnique searches up to the second solution and compares the paths of the search that led to both
+func checkUnique(backend coverBackend, config *searchConfig, a *assumptions) *UniquenessCheck {
+	config.trace.reset()
+	s := newSearcher(backend, config.trace, config.tracer, config.orderer, nil)
+	s.start(a.newPartialSolution())
+
+	result := &UniquenessCheck{}
+	var firstPath []int
+	for s.next() {
+		result.Witnesses = append(result.Witnesses, mapRowNames(s.rows, [][]int{s.partialSolution})[0])
+		if len(result.Witnesses) == 1 {
+			firstPath = append([]int{}, s.path[:s.depth]...)
+			continue
+		}
+
+		// both searches went through the same levels up to the first different candidate
+		for d := range firstPath {
+			if firstPath[d] != s.path[d] {
+				result.DivergingColumn = s.columns[s.levels[d].column]
+				break
+			}
+		}
+		s.stop()
+		break
+	}
+
+	result.Uniqueness = Uniqueness(len(result.Witnesses))
+	return result
+}
@@ -0,0 +1,77 @@
+package dlx
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestCheckUniqueMultiple(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		expected := mat.AsDenseMatrix()
+		check := mat.CheckUnique()
+		assert.Equal(t, UniquenessMultiple, check.Uniqueness)
+		assert.Equal(t, mat.Solve(), check.Witnesses)
+		// nachos is chosen first, Amanda and Jen both bring them
+		assert.Equal(t, "nachos", check.DivergingColumn)
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+	})
+}
+
+func TestCheckUniqueStopsAtTheSecondSolution(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		tracer := &recordingTracer{}
+		mat := newNQueensMatrix(8, backend, WithTracer(tracer))
+		expected := mat.AsDenseMatrix()
+		check := mat.CheckUnique()
+		assert.Equal(t, UniquenessMultiple, check.Uniqueness)
+		assert.Equal(t, 2, len(check.Witnesses))
+		assert.Equal(t, expected, mat.AsDenseMatrix())
+
+		// after the second solution the search only backtracks
+		var solutions int
+		for _, event := range tracer.events {
+			if strings.HasPrefix(event, "solution") {
+				solutions++
+			} else if solutions == 2 {
+				assert.True(t, strings.HasPrefix(event, "backtrack"))
+			}
+		}
+		assert.Equal(t, 2, solutions)
+
+		// both witnesses cover the diverging column, each with another row
+		var rows []string
+		for _, witness := range check.Witnesses {
+			for _, row := range witness {
+				for _, column := range mat.RowColumns(row) {
+					if column == check.DivergingColumn {
+						rows = append(rows, row)
+					}
+				}
+			}
+		}
+		assert.Equal(t, 2, len(rows))
+		assert.NotEqual(t, rows[0], rows[1])
+	})
+}
+
+func TestCheckUniqueUnique(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.Push("Jen"))
+		assert.Equal(t, &UniquenessCheck{Uniqueness: UniquenessUnique, Witnesses: [][]string{{"Jen"}}}, mat.CheckUnique())
+		assert.Nil(t, mat.Pop())
+
+		mat = NewWikipediaExampleMatrix(t, backend)
+		assert.Equal(t, &UniquenessCheck{Uniqueness: UniquenessUnique, Witnesses: mat.Solve()}, mat.CheckUnique())
+	})
+}
+
+func TestCheckUniqueNone(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, backend MatrixOption) {
+		mat := NewReadMeExample(backend)
+		assert.Nil(t, mat.AppendColumn("chips"))
+		assert.Equal(t, &UniquenessCheck{Uniqueness: UniquenessNone}, mat.CheckUnique())
+	})
+}
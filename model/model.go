@@ -0,0 +1,342 @@
+package model
+
+import (
+	"fmt"
+	"github.com/thomasjungblut/go-dancing-links/dlx"
+	"sort"
+)
+
+var NoSolutionError = fmt.Errorf("model has no solution")
+
+type ModelI interface {
+	// adds a new finite-domain variable with the given unique name that has to take exactly one of the domain values.
+	// error is returned when its column "var:<name>" clashes with a column of a constraint.
+	AddVariable(name string, domain []int) (*Variable, error)
+	// every value in the combined domain of the given variables has to be taken by exactly one of them
+	ExactlyOnce(name string, variables ...*Variable) error
+	// at most one of the given assignments can be part of a solution.
+	// The constraints return an error when their columns "<name>" or "<name>:<value>" clash with an existing column.
+	AtMostOnce(name string, assignments ...Assignment) error
+	// no two of the given variables can take the same value
+	AllDifferent(name string, variables ...*Variable) error
+	// compiles the variables and constraints into an exact cover matrix, every row is an assignment of a variable.
+	// every variable and every value of an ExactlyOnce group is a primary column, AtMostOnce and AllDifferent
+	// become secondary columns.
+	Compile() (dlx.DancingLinksMatrixI, error)
+	// maps the rows of a solution of the compiled matrix back to the variable assignments
+	Decode(rows []string) (Solution, error)
+	// compiles and solves the model, returns all solutions or nil and a NoSolutionError if there are none
+	FindAllSolutions() ([]Solution, error)
+	// compiles and solves the model, returns the first solution or nil and a NoSolutionError if there is none
+	FindSingleSolution() (Solution, error)
+}
+
+// Solution maps the name of every variable to its assigned value
+type Solution map[string]int
+
+type Variable struct {
+	name   string
+	domain []int
+}
+
+func (v *Variable) Name() string {
+	return v.name
+}
+
+func (v *Variable) Domain() []int {
+	return v.domain
+}
+
+// Is returns the assignment of the given value to this variable
+func (v *Variable) Is(value int) Assignment {
+	return Assignment{Variable: v, Value: value}
+}
+
+type Assignment struct {
+	Variable *Variable
+	Value    int
+}
+
+func (a Assignment) String() string {
+	return fmt.Sprintf("%s=%d", a.Variable.name, a.Value)
+}
+
+type constraint struct {
+	name      string
+	primary   bool
+	perValue  bool // one column per value, otherwise a single column for all assignments
+	variables []*Variable
+	// only used for constraints that are not per value
+	assignments []Assignment
+}
+
+type Model struct {
+	variables       []*Variable
+	variableIndex   map[string]*Variable
+	constraints     []constraint
+	constraintNames map[string]bool
+	// the names of the columns the variables and constraints compile to, by the name of their owner
+	columnOwners map[string]string
+	// assigned during compilation to decode the solutions
+	rowAssignments map[string]Assignment
+}
+
+func (m *Model) AddVariable(name string, domain []int) (*Variable, error) {
+	if _, ok := m.variableIndex[name]; ok {
+		return nil, fmt.Errorf("variable %s already exists", name)
+	}
+	if len(domain) == 0 {
+		return nil, fmt.Errorf("variable %s has an empty domain", name)
+	}
+
+	seen := map[int]bool{}
+	for _, value := range domain {
+		if seen[value] {
+			return nil, fmt.Errorf("variable %s has duplicate value %d in its domain", name, value)
+		}
+		seen[value] = true
+	}
+	column := variableColumn(name)
+	if err := m.checkColumns("variable "+name, []string{column}); err != nil {
+		return nil, err
+	}
+
+	v := &Variable{name: name, domain: append([]int{}, domain...)}
+	m.columnOwners[column] = "variable " + name
+	m.variables = append(m.variables, v)
+	m.variableIndex[name] = v
+	return v, nil
+}
+
+func (m *Model) ExactlyOnce(name string, variables ...*Variable) error {
+	return m.addConstraint(constraint{name: name, primary: true, perValue: true, variables: variables})
+}
+
+func (m *Model) AtMostOnce(name string, assignments ...Assignment) error {
+	for _, a := range assignments {
+		if a.Variable == nil {
+			return fmt.Errorf("constraint %s: assignment of value %d has no variable", name, a.Value)
+		}
+		if !contains(a.Variable.domain, a.Value) {
+			return fmt.Errorf("constraint %s: value %d is not in the domain of variable %s", name, a.Value, a.Variable.name)
+		}
+	}
+	return m.addConstraint(constraint{name: name, primary: false, assignments: assignments})
+}
+
+func (m *Model) AllDifferent(name string, variables ...*Variable) error {
+	return m.addConstraint(constraint{name: name, primary: false, perValue: true, variables: variables})
+}
+
+func (m *Model) addConstraint(c constraint) error {
+	if m.constraintNames[c.name] {
+		return fmt.Errorf("constraint %s already exists", c.name)
+	}
+	for _, v := range c.variables {
+		if v == nil {
+			return fmt.Errorf("constraint %s: variable must not be nil", c.name)
+		}
+		if m.variableIndex[v.name] != v {
+			return fmt.Errorf("constraint %s: variable %s is not part of this model", c.name, v.name)
+		}
+	}
+	for _, a := range c.assignments {
+		if m.variableIndex[a.Variable.name] != a.Variable {
+			return fmt.Errorf("constraint %s: variable %s is not part of this model", c.name, a.Variable.name)
+		}
+	}
+
+	owner := "constraint " + c.name
+	columns := constraintColumns(c)
+	if err := m.checkColumns(owner, columns); err != nil {
+		return err
+	}
+
+	m.constraintNames[c.name] = true
+	m.constraints = append(m.constraints, c)
+	for _, column := range columns {
+		m.columnOwners[column] = owner
+	}
+	return nil
+}
+
+// checkColumns returns an error when one of the columns is already generated by another variable or constraint,
+// the matrix could not be compiled otherwise
+func (m *Model) checkColumns(owner string, columns []string) error {
+	for _, column := range columns {
+		if other, ok := m.columnOwners[column]; ok {
+			return fmt.Errorf("%s: column %s clashes with %s", owner, column, other)
+		}
+	}
+	return nil
+}
+
+func variableColumn(name string) string {
+	return "var:" + name
+}
+
+// constraintColumns returns the names of the columns the constraint compiles to
+func constraintColumns(c constraint) []string {
+	if !c.perValue {
+		return []string{c.name}
+	}
+	var columns []string
+	for _, value := range combinedDomain(c.variables) {
+		columns = append(columns, fmt.Sprintf("%s:%d", c.name, value))
+	}
+	return columns
+}
+
+func (m *Model) Compile() (dlx.DancingLinksMatrixI, error) {
+	mat := dlx.NewDancingLinkMatrix()
+	// every column is collected with the assignments that cover it, so the rows can be built afterwards
+	var coveringAssignments []map[Assignment]bool
+
+	appendColumn := func(name string, primary bool) error {
+		var err error
+		if primary {
+			err = mat.AppendColumn(name)
+		} else {
+			err = mat.AppendSecondaryColumn(name)
+		}
+		coveringAssignments = append(coveringAssignments, map[Assignment]bool{})
+		return err
+	}
+
+	// every variable takes exactly one value
+	for _, v := range m.variables {
+		if err := appendColumn(variableColumn(v.name), true); err != nil {
+			return nil, err
+		}
+		for _, value := range v.domain {
+			coveringAssignments[len(coveringAssignments)-1][v.Is(value)] = true
+		}
+	}
+
+	for _, c := range m.constraints {
+		columns := constraintColumns(c)
+		if !c.perValue {
+			if err := appendColumn(columns[0], c.primary); err != nil {
+				return nil, err
+			}
+			for _, a := range c.assignments {
+				coveringAssignments[len(coveringAssignments)-1][a] = true
+			}
+			continue
+		}
+
+		for i, value := range combinedDomain(c.variables) {
+			if err := appendColumn(columns[i], c.primary); err != nil {
+				return nil, err
+			}
+			for _, v := range c.variables {
+				if contains(v.domain, value) {
+					coveringAssignments[len(coveringAssignments)-1][v.Is(value)] = true
+				}
+			}
+		}
+	}
+
+	m.rowAssignments = map[string]Assignment{}
+	for _, v := range m.variables {
+		for _, value := range v.domain {
+			a := v.Is(value)
+			row := make([]bool, len(coveringAssignments))
+			for i, assignments := range coveringAssignments {
+				row[i] = assignments[a]
+			}
+			if err := mat.AppendRow(a.String(), row); err != nil {
+				return nil, err
+			}
+			m.rowAssignments[a.String()] = a
+		}
+	}
+
+	return mat, nil
+}
+
+func (m *Model) Decode(rows []string) (Solution, error) {
+	solution := Solution{}
+	for _, row := range rows {
+		a, ok := m.rowAssignments[row]
+		if !ok {
+			return nil, fmt.Errorf("row %s is not an assignment of this model", row)
+		}
+		if _, ok := solution[a.Variable.name]; ok {
+			return nil, fmt.Errorf("variable %s is assigned more than once", a.Variable.name)
+		}
+		solution[a.Variable.name] = a.Value
+	}
+
+	if len(solution) != len(m.variables) {
+		return nil, fmt.Errorf("expected assignments for %d variables, but got %d", len(m.variables), len(solution))
+	}
+
+	return solution, nil
+}
+
+func (m *Model) FindAllSolutions() ([]Solution, error) {
+	mat, err := m.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := mat.Solve()
+	if len(rows) == 0 {
+		return nil, NoSolutionError
+	}
+
+	solutions := make([]Solution, len(rows))
+	for i, r := range rows {
+		solutions[i], err = m.Decode(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return solutions, nil
+}
+
+func (m *Model) FindSingleSolution() (Solution, error) {
+	mat, err := m.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := mat.SolveOne()
+	if rows == nil {
+		return nil, NoSolutionError
+	}
+	return m.Decode(rows)
+}
+
+func combinedDomain(variables []*Variable) []int {
+	seen := map[int]bool{}
+	var values []int
+	for _, v := range variables {
+		for _, value := range v.domain {
+			if !seen[value] {
+				seen[value] = true
+				values = append(values, value)
+			}
+		}
+	}
+	sort.Ints(values)
+	return values
+}
+
+func contains(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func NewModel() ModelI {
+	return &Model{
+		variableIndex:   map[string]*Variable{},
+		constraintNames: map[string]bool{},
+		columnOwners:    map[string]string{},
+	}
+}
@@ -0,0 +1,253 @@
+package model
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSingleVariable(t *testing.T) {
+	m := NewModel()
+	_, err := m.AddVariable("x", []int{1, 2, 3})
+	assert.Nil(t, err)
+
+	solutions, err := m.FindAllSolutions()
+	assert.Nil(t, err)
+	assert.Equal(t, []Solution{{"x": 1}, {"x": 2}, {"x": 3}}, solutions)
+}
+
+func TestExactlyOnceIsAPermutation(t *testing.T) {
+	m := NewModel()
+	var vars []*Variable
+	for i := 0; i < 4; i++ {
+		v, err := m.AddVariable(fmt.Sprintf("x%d", i), []int{1, 2, 3, 4})
+		assert.Nil(t, err)
+		vars = append(vars, v)
+	}
+	assert.Nil(t, m.ExactlyOnce("perm", vars...))
+
+	solutions, err := m.FindAllSolutions()
+	assert.Nil(t, err)
+	// 4! permutations
+	assert.Equal(t, 24, len(solutions))
+	for _, s := range solutions {
+		seen := map[int]bool{}
+		for _, v := range vars {
+			seen[s[v.Name()]] = true
+		}
+		assert.Equal(t, 4, len(seen))
+	}
+}
+
+func TestAllDifferentAllowsUnusedValues(t *testing.T) {
+	m := NewModel()
+	x, _ := m.AddVariable("x", []int{1, 2, 3})
+	y, _ := m.AddVariable("y", []int{1, 2, 3})
+	assert.Nil(t, m.AllDifferent("diff", x, y))
+
+	solutions, err := m.FindAllSolutions()
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(solutions))
+	for _, s := range solutions {
+		assert.NotEqual(t, s["x"], s["y"])
+	}
+}
+
+func TestAtMostOnce(t *testing.T) {
+	m := NewModel()
+	x, _ := m.AddVariable("x", []int{1, 2})
+	y, _ := m.AddVariable("y", []int{1, 2})
+	assert.Nil(t, m.AtMostOnce("not both 2", x.Is(2), y.Is(2)))
+
+	solutions, err := m.FindAllSolutions()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []Solution{{"x": 1, "y": 1}, {"x": 1, "y": 2}, {"x": 2, "y": 1}}, solutions)
+}
+
+func TestNoSolution(t *testing.T) {
+	m := NewModel()
+	x, _ := m.AddVariable("x", []int{1})
+	y, _ := m.AddVariable("y", []int{1})
+	assert.Nil(t, m.AllDifferent("diff", x, y))
+
+	_, err := m.FindAllSolutions()
+	assert.Equal(t, NoSolutionError, err)
+	_, err = m.FindSingleSolution()
+	assert.Equal(t, NoSolutionError, err)
+}
+
+func TestCompile(t *testing.T) {
+	m := NewModel()
+	x, _ := m.AddVariable("x", []int{1, 2})
+	y, _ := m.AddVariable("y", []int{2, 3})
+	assert.Nil(t, m.ExactlyOnce("once", x, y))
+	assert.Nil(t, m.AtMostOnce("pair", x.Is(1), y.Is(3)))
+
+	mat, err := m.Compile()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"var:x", "var:y", "once:1", "once:2", "once:3", "pair"}, mat.Columns())
+	assert.Equal(t, []string{"x=1", "x=2", "y=2", "y=3"}, mat.Rows())
+	assert.Equal(t, [][]bool{
+		{true, false, true, false, false, true},
+		{true, false, false, true, false, false},
+		{false, true, false, true, false, false},
+		{false, true, false, false, true, true},
+	}, mat.AsDenseMatrix())
+
+	// three values for two variables can't be covered exactly once
+	assert.Nil(t, mat.Solve())
+}
+
+func TestDecode(t *testing.T) {
+	m := NewModel()
+	_, _ = m.AddVariable("x", []int{1, 2})
+	_, _ = m.AddVariable("y", []int{1, 2})
+	_, err := m.Compile()
+	assert.Nil(t, err)
+
+	s, err := m.Decode([]string{"x=2", "y=1"})
+	assert.Nil(t, err)
+	assert.Equal(t, Solution{"x": 2, "y": 1}, s)
+
+	_, err = m.Decode([]string{"x=3", "y=1"})
+	assert.EqualError(t, err, "row x=3 is not an assignment of this model")
+	_, err = m.Decode([]string{"x=1", "x=2"})
+	assert.EqualError(t, err, "variable x is assigned more than once")
+	_, err = m.Decode([]string{"x=1"})
+	assert.EqualError(t, err, "expected assignments for 2 variables, but got 1")
+}
+
+func TestValidation(t *testing.T) {
+	m := NewModel()
+	x, err := m.AddVariable("x", []int{1, 2})
+	assert.Nil(t, err)
+	_, err = m.AddVariable("x", []int{1, 2})
+	assert.EqualError(t, err, "variable x already exists")
+	_, err = m.AddVariable("y", []int{})
+	assert.EqualError(t, err, "variable y has an empty domain")
+	_, err = m.AddVariable("y", []int{1, 1})
+	assert.EqualError(t, err, "variable y has duplicate value 1 in its domain")
+
+	assert.Nil(t, m.AllDifferent("c", x))
+	assert.EqualError(t, m.ExactlyOnce("c", x), "constraint c already exists")
+	assert.EqualError(t, m.AtMostOnce("d", x.Is(3)), "constraint d: value 3 is not in the domain of variable x")
+
+	other, _ := NewModel().AddVariable("z", []int{1})
+	assert.EqualError(t, m.ExactlyOnce("e", other), "constraint e: variable z is not part of this model")
+	assert.EqualError(t, m.AtMostOnce("e", other.Is(1)), "constraint e: variable z is not part of this model")
+
+	assert.EqualError(t, m.AtMostOnce("f", Assignment{Value: 1}), "constraint f: assignment of value 1 has no variable")
+	assert.EqualError(t, m.ExactlyOnce("f", x, nil), "constraint f: variable must not be nil")
+}
+
+func TestColumnNameClashes(t *testing.T) {
+	m := NewModel()
+	x, err := m.AddVariable("x", []int{1, 2})
+	assert.Nil(t, err)
+
+	assert.EqualError(t, m.AtMostOnce("var:x", x.Is(1)), "constraint var:x: column var:x clashes with variable x")
+	assert.Nil(t, m.AtMostOnce("var:y", x.Is(1)))
+	_, err = m.AddVariable("y", []int{1})
+	assert.EqualError(t, err, "variable y: column var:y clashes with constraint var:y")
+
+	assert.Nil(t, m.AllDifferent("c", x))
+	assert.EqualError(t, m.AtMostOnce("c:2", x.Is(2)), "constraint c:2: column c:2 clashes with constraint c")
+	assert.Nil(t, m.AtMostOnce("c:3", x.Is(2)))
+
+	// the rejected declarations are not part of the model, so it still compiles
+	_, err = m.Compile()
+	assert.Nil(t, err)
+	solutions, err := m.FindAllSolutions()
+	assert.Nil(t, err)
+	assert.Equal(t, []Solution{{"x": 1}, {"x": 2}}, solutions)
+}
+
+func TestNQueensModel(t *testing.T) {
+	// https://oeis.org/A000170
+	expectedResultSizes := []int{1, 0, 0, 2, 10, 4, 40, 92}
+	for i, expected := range expectedResultSizes {
+		n := i + 1
+		m := NewModel()
+		var domain []int
+		for c := 0; c < n; c++ {
+			domain = append(domain, c)
+		}
+
+		// one queen per row that picks its column
+		queens := make([]*Variable, n)
+		for r := 0; r < n; r++ {
+			queens[r], _ = m.AddVariable(fmt.Sprintf("q%d", r), domain)
+		}
+		assert.Nil(t, m.ExactlyOnce("col", queens...))
+
+		for d := 0; d < 2*n-1; d++ {
+			var diagonal, reverse []Assignment
+			for r := 0; r < n; r++ {
+				if c := d - r; c >= 0 && c < n {
+					diagonal = append(diagonal, queens[r].Is(c))
+				}
+				if c := d - (n - 1) + r; c >= 0 && c < n {
+					reverse = append(reverse, queens[r].Is(c))
+				}
+			}
+			assert.Nil(t, m.AtMostOnce(fmt.Sprintf("d%d", d), diagonal...))
+			assert.Nil(t, m.AtMostOnce(fmt.Sprintf("rd%d", d), reverse...))
+		}
+
+		solutions, err := m.FindAllSolutions()
+		if expected == 0 {
+			assert.Equal(t, NoSolutionError, err)
+		} else {
+			assert.Nil(t, err)
+		}
+		assert.Equal(t, expected, len(solutions), "n = %d", n)
+	}
+}
+
+func TestSudokuModel(t *testing.T) {
+	grid := `003020600
+900305001
+001806400
+008102900
+700000008
+006708200
+002609500
+800203009
+005010300`
+
+	m := NewModel()
+	digits := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	cells := make([][]*Variable, 9)
+	for r, line := range strings.Split(grid, "\n") {
+		cells[r] = make([]*Variable, 9)
+		for c, ch := range line {
+			domain := digits
+			if ch != '0' {
+				domain = []int{int(ch - '0')}
+			}
+			cells[r][c], _ = m.AddVariable(fmt.Sprintf("%d_%d", r, c), domain)
+		}
+	}
+
+	for i := 0; i < 9; i++ {
+		var row, col, box []*Variable
+		for j := 0; j < 9; j++ {
+			row = append(row, cells[i][j])
+			col = append(col, cells[j][i])
+			box = append(box, cells[(i/3)*3+j/3][(i%3)*3+j%3])
+		}
+		assert.Nil(t, m.ExactlyOnce(fmt.Sprintf("row%d", i), row...))
+		assert.Nil(t, m.ExactlyOnce(fmt.Sprintf("col%d", i), col...))
+		assert.Nil(t, m.ExactlyOnce(fmt.Sprintf("box%d", i), box...))
+	}
+
+	solution, err := m.FindSingleSolution()
+	assert.Nil(t, err)
+	assert.Equal(t, 81, len(solution))
+	firstRow := make([]int, 9)
+	for c := 0; c < 9; c++ {
+		firstRow[c] = solution[fmt.Sprintf("0_%d", c)]
+	}
+	assert.Equal(t, []int{4, 8, 3, 9, 2, 1, 6, 5, 7}, firstRow)
+}
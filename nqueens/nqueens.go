@@ -18,10 +18,22 @@ type NQueensBoardI interface {
 	FindAllSolutions() ([]NQueensBoardI, error)
 	// solves the n-queens problem with DLX and returns the count of the solutions
 	CountAllSolutions() (int, error)
+	// solves the n-queens problem with DLX and returns one solution of every class of solutions that are rotations
+	// or reflections of each other, together with the size of the class.
+	FindFundamentalSolutions() ([]FundamentalSolution, error)
 	// returns the given board as a dense two dimensional array, where true denotes a placed queen
 	AsTwoDimArray() [][]bool
 }
 
+// FundamentalSolution is a solution that stands for all of its rotations and reflections
+type FundamentalSolution struct {
+	Board NQueensBoardI
+	// the number of distinct solutions among the rotations and reflections of the board, including itself
+	NumSymmetric int
+}
+
+var queenRegex = regexp.MustCompile(`queen_(\d+)_(\d+)`)
+
 type placementCoordinate struct {
 	row, col int
 }
@@ -29,6 +41,7 @@ type placementCoordinate struct {
 type NQueensBoard struct {
 	placements map[placementCoordinate]bool
 	n          int
+	options    []dlx.MatrixOption
 }
 
 func (b *NQueensBoard) AsTwoDimArray() [][]bool {
@@ -136,25 +149,36 @@ func (b *NQueensBoard) VerifyCorrectness() error {
 	return nil
 }
 
-func (b *NQueensBoard) solve() [][]string {
-	mat := dlx.NewDancingLinkMatrix()
+func (b *NQueensBoard) createDancingLinksMatrix() (dlx.DancingLinksMatrixI, error) {
+	// every placement is true in its row, column and both diagonals
+	numColumns := 6*b.n - 2
+	shape := dlx.WithShape(numColumns, 4/float64(numColumns))
+	mat := dlx.NewDancingLinkMatrix(append([]dlx.MatrixOption{shape}, b.options...)...)
 
 	// add the row and col constraints
 	for i := 0; i < b.n; i++ {
-		mat.AppendColumn(fmt.Sprintf("r_%d", i))
+		if err := mat.AppendColumn(fmt.Sprintf("r_%d", i)); err != nil {
+			return nil, err
+		}
 	}
 
 	for i := 0; i < b.n; i++ {
-		mat.AppendColumn(fmt.Sprintf("c_%d", i))
+		if err := mat.AppendColumn(fmt.Sprintf("c_%d", i)); err != nil {
+			return nil, err
+		}
 	}
 
 	// left bottom to top right diag
 	for i := 0; i < 2*b.n-1; i++ {
-		mat.AppendSecondaryColumn(fmt.Sprintf("d_%d", i))
+		if err := mat.AppendSecondaryColumn(fmt.Sprintf("d_%d", i)); err != nil {
+			return nil, err
+		}
 	}
 	// reversed
 	for i := 0; i < 2*b.n-1; i++ {
-		mat.AppendSecondaryColumn(fmt.Sprintf("rd_%d", i))
+		if err := mat.AppendSecondaryColumn(fmt.Sprintf("rd_%d", i)); err != nil {
+			return nil, err
+		}
 	}
 
 	numConstraints := len(mat.Columns())
@@ -172,39 +196,78 @@ func (b *NQueensBoard) solve() [][]string {
 			constraint[2*b.n+r+c] = true
 			constraint[(4*b.n-1)+(b.n-r+c-1)] = true
 
-			_ = mat.AppendRow(fmt.Sprintf("queen_%d_%d", r, c), constraint)
+			if err := mat.AppendRow(fmt.Sprintf("queen_%d_%d", r, c), constraint); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return mat, nil
+}
+
+// symmetries returns the rotation by 90 degrees and the horizontal reflection of the board in terms of the matrix,
+// together they generate all eight symmetries of the square.
+func (b *NQueensBoard) symmetries() []dlx.Symmetry {
+	n := b.n
+	if n < 1 {
+		return nil
+	}
+	rotation := dlx.Symmetry{Rows: make([]int, n*n), Columns: make([]int, 6*n-2)}
+	reflection := dlx.Symmetry{Rows: make([]int, n*n), Columns: make([]int, 6*n-2)}
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			// (r, c) rotates to (c, n-1-r) and reflects to (r, n-1-c)
+			rotation.Rows[r*n+c] = c*n + n - 1 - r
+			reflection.Rows[r*n+c] = r*n + n - 1 - c
 		}
 	}
 
-	return mat.Solve()
+	// the same coordinate math as for the rows of the matrix, see createDancingLinksMatrix
+	rowConstraint := func(i int) int { return i }
+	colConstraint := func(i int) int { return n + i }
+	diagConstraint := func(i int) int { return 2*n + i }
+	reverseDiagConstraint := func(i int) int { return 4*n - 1 + i }
+	for i := 0; i < n; i++ {
+		rotation.Columns[rowConstraint(i)] = colConstraint(n - 1 - i)
+		rotation.Columns[colConstraint(i)] = rowConstraint(i)
+		reflection.Columns[rowConstraint(i)] = rowConstraint(i)
+		reflection.Columns[colConstraint(i)] = colConstraint(n - 1 - i)
+	}
+	for i := 0; i < 2*n-1; i++ {
+		rotation.Columns[diagConstraint(i)] = reverseDiagConstraint(2*n - 2 - i)
+		rotation.Columns[reverseDiagConstraint(i)] = diagConstraint(i)
+		reflection.Columns[diagConstraint(i)] = reverseDiagConstraint(2*n - 2 - i)
+		reflection.Columns[reverseDiagConstraint(i)] = diagConstraint(2*n - 2 - i)
+	}
+	return []dlx.Symmetry{rotation, reflection}
+}
+
+func (b *NQueensBoard) solve() ([][]string, error) {
+	mat, err := b.createDancingLinksMatrix()
+	if err != nil {
+		return nil, err
+	}
+	return mat.Solve(), nil
 }
 
 func (b *NQueensBoard) CountAllSolutions() (int, error) {
-	return len(b.solve()), nil
+	solutions, err := b.solve()
+	if err != nil {
+		return 0, err
+	}
+	return len(solutions), nil
 }
 
 func (b *NQueensBoard) FindAllSolutions() ([]NQueensBoardI, error) {
-	solutions := b.solve()
+	solutions, err := b.solve()
+	if err != nil {
+		return nil, err
+	}
 	var resultBoards []NQueensBoardI
-	regex := regexp.MustCompile(`queen_(\d+)_(\d+)`)
 	for _, solution := range solutions {
-		if len(solution) != b.n {
-			return nil, errors.New(fmt.Sprintf("didn't expect %d queens on an %d board", len(solution), b.n))
-		}
-		resultBoard := &NQueensBoard{n: b.n, placements: map[placementCoordinate]bool{}}
-		for _, s := range solution {
-			subMatch := regex.FindStringSubmatch(s)
-			if subMatch != nil {
-				row, err := strconv.Atoi(subMatch[1])
-				if err != nil {
-					return nil, err
-				}
-				col, err := strconv.Atoi(subMatch[2])
-				if err != nil {
-					return nil, err
-				}
-				resultBoard.placements[placementCoordinate{row: row, col: col}] = true
-			}
+		resultBoard, err := b.boardFromSolution(solution)
+		if err != nil {
+			return nil, err
 		}
 		resultBoards = append(resultBoards, resultBoard)
 	}
@@ -212,8 +275,63 @@ func (b *NQueensBoard) FindAllSolutions() ([]NQueensBoardI, error) {
 	return resultBoards, nil
 }
 
-func NewNQueensBoard(n int) NQueensBoardI {
-	return &NQueensBoard{n: n, placements: map[placementCoordinate]bool{}}
+func (b *NQueensBoard) FindFundamentalSolutions() ([]FundamentalSolution, error) {
+	mat, err := b.createDancingLinksMatrix()
+	if err != nil {
+		return nil, err
+	}
+	solutions, err := mat.SolveUpToSymmetry(b.symmetries())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []FundamentalSolution
+	for _, solution := range solutions {
+		resultBoard, err := b.boardFromSolution(solution.Rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, FundamentalSolution{Board: resultBoard, NumSymmetric: solution.OrbitSize})
+	}
+
+	return result, nil
+}
+
+func (b *NQueensBoard) boardFromSolution(solution []string) (*NQueensBoard, error) {
+	if len(solution) != b.n {
+		return nil, errors.New(fmt.Sprintf("didn't expect %d queens on an %d board", len(solution), b.n))
+	}
+	resultBoard := &NQueensBoard{n: b.n, placements: map[placementCoordinate]bool{}}
+	for _, s := range solution {
+		subMatch := queenRegex.FindStringSubmatch(s)
+		if subMatch != nil {
+			row, err := strconv.Atoi(subMatch[1])
+			if err != nil {
+				return nil, err
+			}
+			col, err := strconv.Atoi(subMatch[2])
+			if err != nil {
+				return nil, err
+			}
+			resultBoard.placements[placementCoordinate{row: row, col: col}] = true
+		}
+	}
+	return resultBoard, nil
+}
+
+// NewNQueensBoard creates an empty board of size n, the options are passed to the DLX matrix that solves it.
+// The matrix is created with the shape of the board, so boards up to 33 queens run on the bitset backend unless the
+// options select another one with dlx.WithBackend.
+func NewNQueensBoard(n int, options ...dlx.MatrixOption) NQueensBoardI {
+	return &NQueensBoard{n: n, placements: map[placementCoordinate]bool{}, options: options}
+}
+
+// NewNQueensMatrix creates the DLX matrix of the n-queens problem on a board of size n: one row per square, a primary
+// column for every row and column of the board and a secondary column for every diagonal. The backend is picked
+// like in NewNQueensBoard.
+func NewNQueensMatrix(n int, options ...dlx.MatrixOption) (dlx.DancingLinksMatrixI, error) {
+	b := &NQueensBoard{n: n, placements: map[placementCoordinate]bool{}, options: options}
+	return b.createDancingLinksMatrix()
 }
 
 func newTestingNQueensBoard(a [][]bool) NQueensBoardI {
@@ -2,6 +2,7 @@ package nqueens
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/thomasjungblut/go-dancing-links/dlx"
 	"os"
 	"strings"
 	"testing"
@@ -36,6 +37,52 @@ func TestNQueensResultSizesAndCheckCorrectness(t *testing.T) {
 	}
 }
 
+func TestNQueensMatrix(t *testing.T) {
+	mat, err := NewNQueensMatrix(8, dlx.WithBackend(dlx.DancingCellsBackend))
+	assert.Nil(t, err)
+	assert.IsType(t, &dlx.DancingCellsMatrix{}, mat)
+	assert.Equal(t, 6*8-2, len(mat.Columns()))
+	assert.Equal(t, 8*8, len(mat.Rows()))
+	assert.Equal(t, 92, mat.Count())
+}
+
+// the shape of the board picks the backend unless it is given
+func TestNQueensMatrixDefaultBackend(t *testing.T) {
+	mat, err := NewNQueensMatrix(33)
+	assert.Nil(t, err)
+	assert.IsType(t, &dlx.BitsetMatrix{}, mat)
+	mat, err = NewNQueensMatrix(34)
+	assert.Nil(t, err)
+	assert.IsType(t, &dlx.DancingLinksMatrix{}, mat)
+	mat, err = NewNQueensMatrix(8, dlx.WithBackend(dlx.LinkedListBackend))
+	assert.Nil(t, err)
+	assert.IsType(t, &dlx.DancingLinksMatrix{}, mat)
+}
+
+func TestFundamentalSolutions(t *testing.T) {
+	// https://oeis.org/A002562 and https://oeis.org/A000170
+	expectedFundamental := []int{1, 1, 0, 0, 1, 2, 1, 6, 12, 46, 92}
+	expectedAll := []int{1, 1, 0, 0, 2, 10, 4, 40, 92, 352, 724}
+
+	for i := 0; i < len(expectedFundamental); i++ {
+		result, err := NewNQueensBoard(i).FindFundamentalSolutions()
+		assert.Nil(t, err)
+		assert.Equal(t, expectedFundamental[i], len(result), "n = %d", i)
+		total := 0
+		for _, fundamental := range result {
+			assert.Nil(t, fundamental.Board.VerifyCorrectness(), "n = %d", i)
+			total += fundamental.NumSymmetric
+		}
+		assert.Equal(t, expectedAll[i], total, "n = %d", i)
+	}
+
+	// the only fundamental solution of the 6 queens problem is symmetric under a half turn
+	result, err := NewNQueensBoard(6).FindFundamentalSolutions()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, 4, result[0].NumSymmetric)
+}
+
 func TestPrintingHappyPath(t *testing.T) {
 	board := newTestingNQueensBoard([][]bool{
 		{true, false, false},
@@ -97,3 +144,16 @@ func TestVerifyWrongResultFailsWrongQueenCount(t *testing.T) {
 	})
 	assert.EqualError(t, board.VerifyCorrectness(), "unexpected number of queens: size is 1, but defined length is 2")
 }
+
+func TestNQueensDancingCellsBackend(t *testing.T) {
+	expectedResultSizes := []int{1, 1, 0, 0, 2, 10, 4, 40, 92}
+	for i, expected := range expectedResultSizes {
+		board := NewNQueensBoard(i, dlx.WithBackend(dlx.DancingCellsBackend))
+		result, err := board.FindAllSolutions()
+		assert.Nil(t, err)
+		assert.Equal(t, expected, len(result), "n = %d", i)
+		for _, board := range result {
+			assert.Nil(t, board.VerifyCorrectness(), "n = %d", i)
+		}
+	}
+}
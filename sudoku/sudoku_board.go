@@ -28,8 +28,9 @@ type SudokuBoardI interface {
 }
 
 type SudokuBoard struct {
-	board [][]int
-	size  int
+	board   [][]int
+	size    int
+	options []dlx.MatrixOption
 }
 
 func (b *SudokuBoard) FindSingleSolution() (SudokuBoardI, error) {
@@ -73,7 +74,7 @@ func (b *SudokuBoard) mapSolutionsToBoards(solutions [][]string) ([]SudokuBoardI
 			board[i] = make([]int, b.size)
 			copy(board[i], b.board[i])
 		}
-		resultBoard := &SudokuBoard{size: b.size, board: board}
+		resultBoard := &SudokuBoard{size: b.size, board: board, options: b.options}
 
 		for _, s := range solution {
 			subMatch := regex.FindStringSubmatch(s)
@@ -103,18 +104,25 @@ func (b *SudokuBoard) createDancingLinksMatrix() (dlx.DancingLinksMatrixI, error
 	squareYSize := int(math.Sqrt(float64(b.size)))
 	squareXSize := b.size / squareYSize
 
-	mat := dlx.NewDancingLinkMatrix()
+	// every cell candidate is true in exactly one column of each of the four constraint kinds
+	numColumns := 4 * b.size * b.size
+	shape := dlx.WithShape(numColumns, 4/float64(numColumns))
+	mat := dlx.NewDancingLinkMatrix(append([]dlx.MatrixOption{shape}, b.options...)...)
 	// column constraints
 	for col := 0; col < b.size; col++ {
 		for num := 1; num <= b.size; num++ {
-			mat.AppendColumn(fmt.Sprintf("col_%d_%d", num, col))
+			if err := mat.AppendColumn(fmt.Sprintf("col_%d_%d", num, col)); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	// row constraints
 	for row := 0; row < b.size; row++ {
 		for num := 1; num <= b.size; num++ {
-			mat.AppendColumn(fmt.Sprintf("row_%d_%d", num, row))
+			if err := mat.AppendColumn(fmt.Sprintf("row_%d_%d", num, row)); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -122,7 +130,9 @@ func (b *SudokuBoard) createDancingLinksMatrix() (dlx.DancingLinksMatrixI, error
 	for row := 0; row < squareXSize; row++ {
 		for col := 0; col < squareYSize; col++ {
 			for num := 1; num <= b.size; num++ {
-				mat.AppendColumn(fmt.Sprintf("sq_%d_%d_%d", num, row, col))
+				if err := mat.AppendColumn(fmt.Sprintf("sq_%d_%d_%d", num, row, col)); err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
@@ -130,7 +140,9 @@ func (b *SudokuBoard) createDancingLinksMatrix() (dlx.DancingLinksMatrixI, error
 	// all cell constraints
 	for row := 0; row < b.size; row++ {
 		for col := 0; col < b.size; col++ {
-			mat.AppendColumn(fmt.Sprintf("cell_%d_%d", row, col))
+			if err := mat.AppendColumn(fmt.Sprintf("cell_%d_%d", row, col)); err != nil {
+				return nil, err
+			}
 		}
 	}
 
@@ -260,10 +272,13 @@ func (b *SudokuBoard) Print(writer io.StringWriter) error {
 	return nil
 }
 
-func NewSudokuBoard(size int) SudokuBoardI {
+// NewSudokuBoard creates an empty board of the given size, the options are passed to the DLX matrix that solves it.
+// The matrix is created with the shape of the board, so small boards run on the bitset backend unless the options
+// select another one with dlx.WithBackend.
+func NewSudokuBoard(size int, options ...dlx.MatrixOption) SudokuBoardI {
 	board := make([][]int, size, size)
 	for i := 0; i < size; i++ {
 		board[i] = make([]int, size)
 	}
-	return &SudokuBoard{size: size, board: board}
+	return &SudokuBoard{size: size, board: board, options: options}
 }
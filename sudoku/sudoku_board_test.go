@@ -2,6 +2,7 @@ package sudoku
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/thomasjungblut/go-dancing-links/dlx"
 	"strings"
 	"testing"
 )
@@ -30,6 +31,27 @@ func TestSolvingHappyPath(t *testing.T) {
 	assert.Nil(t, board.VerifyCorrectness())
 }
 
+func TestSolvingDancingCellsBackend(t *testing.T) {
+	board := NewSudokuBoard(9, dlx.WithBackend(dlx.DancingCellsBackend))
+	assert.Nil(t, board.ReadEulerTextFormat(`Grid 01
+003020600
+900305001
+001806400
+008102900
+700000008
+006708200
+002609500
+800203009
+005010300`))
+	solution, err := board.FindSingleSolution()
+	assert.Nil(t, err)
+	assert.Nil(t, solution.VerifyCorrectness())
+
+	boards, err := multiSolutionGrid(t).FindAllSolutions()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(boards))
+}
+
 func TestSudokuCorrectnessFailsRowConstraint(t *testing.T) {
 	board := NewSudokuBoard(9)
 	assert.Nil(t, board.ReadEulerTextFormat(`Grid00